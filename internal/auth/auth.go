@@ -0,0 +1,161 @@
+package auth
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Nicolas-Rigaudy/lazytf/internal/aws"
+	"github.com/Nicolas-Rigaudy/lazytf/internal/config"
+)
+
+// Provider logs in to a cloud and tells which identity and context commands run with
+type Provider interface {
+	ID() string   // "aws", "google" or "azure"
+	Name() string // display name, e.g. "Google Cloud"
+
+	// Status reads the login state of the environment from the CLI's local files
+	Status(ctx Context) Status
+	// Logins returns the ways to log in for the environment, the first being the default
+	Logins(ctx Context) []Login
+	// Env returns the variables commands for the environment run with
+	Env(ctx Context) []string
+}
+
+// ContextPicker is implemented by providers whose context can be switched after
+// logging in, e.g. the Azure subscription
+type ContextPicker interface {
+	ContextName() string // e.g. "subscription"
+	Contexts(ctx Context) ([]Choice, error)
+}
+
+// Choice is a context a ContextPicker offers
+type Choice struct {
+	ID    string
+	Label string
+}
+
+// Context is the environment a provider works for
+type Context struct {
+	Config          config.Config
+	ProjectPath     string
+	EnvName         string
+	AWSProfile      string                          // profile picked with 'a', for environments without a mapping
+	AWSRegion       string                          // region picked with 'r' for the environment
+	AWSProfiles     []*aws.Profile                  // known profiles, for their region
+	BackendRegion   string                          // region setting of the environment's backend file
+	RoleCredentials map[string]*aws.RoleCredentials // profile name -> credentials obtained with an MFA code
+	Picked          map[string]string               // provider ID -> context picked with its ContextPicker for this environment
+	NoBrowser       bool                            // log in without opening a browser on this machine
+}
+
+// Status is the login state of a provider
+type Status struct {
+	LoggedIn bool
+	Text     string // e.g. "user@example.com", "3h12m left", "not logged in"; "" when there is nothing to show
+}
+
+// Login is a command that logs in
+type Login struct {
+	Label      string // e.g. "gcloud auth application-default login"
+	Command    string
+	Args       []string
+	DeviceCode bool // the output gives a URL and code to open elsewhere (see aws.DeviceAuthorization)
+}
+
+// All returns every supported provider
+func All() []Provider {
+	return []Provider{AWS{}, Google{}, Azure{}}
+}
+
+// Find returns the provider with the given ID, or nil if there is none
+func Find(id string) Provider {
+	for _, provider := range All() {
+		if provider.ID() == id {
+			return provider
+		}
+	}
+	return nil
+}
+
+// terraformProviders maps Terraform provider names to the provider logging in for them
+var terraformProviders = map[string]string{
+	"aws":         "aws",
+	"google":      "google",
+	"google-beta": "google",
+	"azurerm":     "azure",
+	"azuread":     "azure",
+	"azapi":       "azure",
+}
+
+// ForTerraformProviders returns the providers needed by a project using the given
+// Terraform providers (see terraform.UsedProviders), in the order of All
+func ForTerraformProviders(names []string) []Provider {
+	needed := make(map[string]bool)
+	for _, name := range names {
+		if id, ok := terraformProviders[name]; ok {
+			needed[id] = true
+		}
+	}
+	var providers []Provider
+	for _, provider := range All() {
+		if needed[provider.ID()] {
+			providers = append(providers, provider)
+		}
+	}
+	return providers
+}
+
+// Env returns the variables of every provider for the environment
+// Providers only add variables when the environment is mapped or something was picked
+func Env(ctx Context) []string {
+	var env []string
+	for _, provider := range All() {
+		env = append(env, provider.Env(ctx)...)
+	}
+	return env
+}
+
+// recentStatuses caches Status results for a few seconds, so the header
+// doesn't read the CLIs' files on every render
+var recentStatuses = struct {
+	sync.Mutex
+	entries map[string]recentStatus
+}{entries: make(map[string]recentStatus)}
+
+type recentStatus struct {
+	status Status
+	readAt time.Time
+}
+
+// RecentStatus is provider.Status with results reused for up to 10 seconds
+func RecentStatus(provider Provider, ctx Context) Status {
+	key := provider.ID() + "|" + strings.Join(provider.Env(ctx), " ") + "|" + pickedKey(ctx.Picked)
+	recentStatuses.Lock()
+	defer recentStatuses.Unlock()
+
+	if entry, ok := recentStatuses.entries[key]; ok && time.Since(entry.readAt) < 10*time.Second {
+		return entry.status
+	}
+	status := provider.Status(ctx)
+	recentStatuses.entries[key] = recentStatus{status: status, readAt: time.Now()}
+	return status
+}
+
+// ForgetStatuses drops the cached statuses, e.g. after a login
+func ForgetStatuses() {
+	recentStatuses.Lock()
+	defer recentStatuses.Unlock()
+	recentStatuses.entries = make(map[string]recentStatus)
+}
+
+// pickedKey serializes the picked contexts for cache keys
+func pickedKey(picked map[string]string) string {
+	var parts []string
+	for id, value := range picked {
+		parts = append(parts, id+"="+value)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,149 @@
+package auth
+
+import (
+	"strings"
+
+	"github.com/Nicolas-Rigaudy/lazytf/internal/aws"
+)
+
+// AWS logs in with `aws sso login`
+type AWS struct{}
+
+func (AWS) ID() string   { return "aws" }
+func (AWS) Name() string { return "AWS" }
+
+// AWSSettings returns the AWS profile and region for the environment
+// The profile is the one of its mapping from the config, falling back to the profile picked with 'a'
+func AWSSettings(ctx Context) (string, string) {
+	region, _ := AWSRegion(ctx)
+	return awsProfile(ctx), region
+}
+
+// awsProfile returns the profile of the environment's mapping, or the profile picked with 'a'
+func awsProfile(ctx Context) string {
+	if mapping, ok := ctx.Config.AWSMappingFor(ctx.ProjectPath, ctx.EnvName); ok && mapping.Profile != "" {
+		return mapping.Profile
+	}
+	return ctx.AWSProfile
+}
+
+// AWSRegion returns the region commands for the environment run in and where it comes from:
+// "picked" with the region switcher, "config" mapping, "profile" or "backend" file
+func AWSRegion(ctx Context) (string, string) {
+	if ctx.AWSRegion != "" {
+		return ctx.AWSRegion, "picked"
+	}
+	if mapping, ok := ctx.Config.AWSMappingFor(ctx.ProjectPath, ctx.EnvName); ok && mapping.Region != "" {
+		return mapping.Region, "config"
+	}
+	if profile := aws.FindProfile(ctx.AWSProfiles, awsProfile(ctx)); profile != nil && profile.Region != "" {
+		return profile.Region, "profile"
+	}
+	if ctx.BackendRegion != "" {
+		return ctx.BackendRegion, "backend"
+	}
+	return "", ""
+}
+
+// ssoSession returns the SSO session of the environment's profile, or the only
+// configured session when no profile is set
+func (AWS) ssoSession(ctx Context, shared *aws.SharedConfig) *aws.SSOSession {
+	profile, _ := AWSSettings(ctx)
+	if profile != "" {
+		return aws.SessionForProfile(aws.FindProfile(shared.Profiles, profile), shared.Sessions)
+	}
+	if len(shared.Sessions) == 1 {
+		return shared.Sessions[0]
+	}
+	return nil
+}
+
+// Status tells how long the SSO token or the MFA session stays valid
+// Profiles that use neither are assumed to be logged in
+func (p AWS) Status(ctx Context) Status {
+	shared, err := aws.LoadSharedConfig()
+	if err != nil {
+		return Status{LoggedIn: true}
+	}
+	profile, _ := AWSSettings(ctx)
+	if aws.NeedsMFA(shared.Profiles, aws.FindProfile(shared.Profiles, profile)) {
+		if credentials := validRoleCredentials(ctx, profile); credentials != nil {
+			return Status{LoggedIn: true, Text: "MFA session " + credentials.FormatExpiry()}
+		}
+		return Status{Text: "MFA code needed"}
+	}
+	session := p.ssoSession(ctx, shared)
+	if session == nil {
+		return Status{LoggedIn: true}
+	}
+	token, _ := aws.FindSSOToken(session)
+	return Status{LoggedIn: token != nil && !token.Expired(), Text: aws.FormatTimeToExpiry(token)}
+}
+
+// Logins returns one login per SSO session, the environment's own session first
+func (p AWS) Logins(ctx Context) []Login {
+	shared, err := aws.LoadSharedConfig()
+	if err != nil {
+		return nil
+	}
+	sessions := shared.Sessions
+	if current := p.ssoSession(ctx, shared); current != nil {
+		sessions = []*aws.SSOSession{current}
+		for _, session := range shared.Sessions {
+			if session.Name != current.Name || current.Profile != "" {
+				sessions = append(sessions, session)
+			}
+		}
+	}
+
+	// Without a local browser the device code flow works everywhere, --no-browser alone
+	// needs the PKCE redirect to reach this machine
+	mode := aws.ParseLoginMode(ctx.Config.SSOLoginMode)
+	if ctx.NoBrowser && mode == aws.LoginBrowser {
+		mode = aws.LoginDeviceCode
+	}
+	var logins []Login
+	for _, session := range sessions {
+		logins = append(logins, AWSLogin(session, mode))
+	}
+	return logins
+}
+
+// AWSLogin returns the login of an SSO session
+func AWSLogin(session *aws.SSOSession, mode aws.LoginMode) Login {
+	args := aws.SSOLoginArgs(session, mode)
+	return Login{
+		Label:      "aws " + strings.Join(args, " "),
+		Command:    "aws",
+		Args:       args,
+		DeviceCode: mode != aws.LoginBrowser,
+	}
+}
+
+// validRoleCredentials returns the unexpired MFA credentials of a profile, nil if there are none
+func validRoleCredentials(ctx Context, profile string) *aws.RoleCredentials {
+	credentials := ctx.RoleCredentials[profile]
+	if credentials == nil || credentials.Expired() {
+		return nil
+	}
+	return credentials
+}
+
+// Env passes the environment's profile and region, or the credentials obtained
+// for the profile with an MFA code so commands don't prompt for one
+func (AWS) Env(ctx Context) []string {
+	var env []string
+	profile, region := AWSSettings(ctx)
+	if credentials := validRoleCredentials(ctx, profile); credentials != nil {
+		env = append(env, credentials.Env()...)
+		if region == "" {
+			region = credentials.Region
+		}
+	} else if profile != "" {
+		env = append(env, "AWS_PROFILE="+profile)
+	}
+	if region != "" {
+		env = append(env, "AWS_REGION="+region, "AWS_DEFAULT_REGION="+region)
+	}
+	return env
+}
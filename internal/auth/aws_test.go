@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Nicolas-Rigaudy/lazytf/internal/aws"
+	"github.com/Nicolas-Rigaudy/lazytf/internal/config"
+)
+
+func TestAWSEnv(t *testing.T) {
+	cfg := config.Config{AWS: []config.AWSMapping{
+		{Env: "prod", Profile: "prod-admin", Region: "eu-west-1"},
+		{Env: "staging", Region: "eu-west-3"},
+	}}
+
+	tests := []struct {
+		name       string
+		envName    string
+		awsProfile string // picked with 'a'
+		want       []string
+	}{
+		{
+			name:       "mapping wins over the picked profile",
+			envName:    "prod",
+			awsProfile: "sandbox",
+			want:       []string{"AWS_PROFILE=prod-admin", "AWS_REGION=eu-west-1", "AWS_DEFAULT_REGION=eu-west-1"},
+		},
+		{
+			name:       "picked profile for a mapping without one",
+			envName:    "staging",
+			awsProfile: "sandbox",
+			want:       []string{"AWS_PROFILE=sandbox", "AWS_REGION=eu-west-3", "AWS_DEFAULT_REGION=eu-west-3"},
+		},
+		{name: "picked profile without a mapping", envName: "dev", awsProfile: "sandbox", want: []string{"AWS_PROFILE=sandbox"}},
+		{name: "nothing to pass", envName: "dev"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AWS{}.Env(Context{Config: cfg, EnvName: tt.envName, AWSProfile: tt.awsProfile})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Env() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAWSRegion(t *testing.T) {
+	cfg := config.Config{AWS: []config.AWSMapping{
+		{Env: "prod", Profile: "prod-admin", Region: "eu-west-1"},
+		{Env: "staging", Profile: "staging-admin"},
+	}}
+	profiles := []*aws.Profile{
+		{Name: "prod-admin", Region: "us-east-1"},
+		{Name: "staging-admin", Region: "eu-west-3"},
+		{Name: "sandbox"},
+	}
+
+	tests := []struct {
+		name          string
+		envName       string
+		awsProfile    string // picked with 'a'
+		awsRegion     string // picked with 'r'
+		backendRegion string
+		want          string
+		wantSource    string
+	}{
+		{name: "picked wins", envName: "prod", awsRegion: "ap-south-1", backendRegion: "eu-north-1", want: "ap-south-1", wantSource: "picked"},
+		{name: "config mapping", envName: "prod", backendRegion: "eu-north-1", want: "eu-west-1", wantSource: "config"},
+		{name: "mapped profile", envName: "staging", backendRegion: "eu-north-1", want: "eu-west-3", wantSource: "profile"},
+		{name: "picked profile", envName: "dev", awsProfile: "prod-admin", backendRegion: "eu-north-1", want: "us-east-1", wantSource: "profile"},
+		{name: "backend file", envName: "dev", awsProfile: "sandbox", backendRegion: "eu-north-1", want: "eu-north-1", wantSource: "backend"},
+		{name: "unknown profile", envName: "dev", awsProfile: "gone", backendRegion: "eu-north-1", want: "eu-north-1", wantSource: "backend"},
+		{name: "none", envName: "dev"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := Context{
+				Config:        cfg,
+				EnvName:       tt.envName,
+				AWSProfile:    tt.awsProfile,
+				AWSRegion:     tt.awsRegion,
+				AWSProfiles:   profiles,
+				BackendRegion: tt.backendRegion,
+			}
+			region, source := AWSRegion(ctx)
+			if region != tt.want || source != tt.wantSource {
+				t.Errorf("AWSRegion() = %q, %q, want %q, %q", region, source, tt.want, tt.wantSource)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// Azure logs in with `az login`, the subscription being picked afterwards
+type Azure struct{}
+
+func (Azure) ID() string          { return "azure" }
+func (Azure) Name() string        { return "Azure" }
+func (Azure) ContextName() string { return "subscription" }
+
+// azureSubscription is an entry of ~/.azure/azureProfile.json
+type azureSubscription struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	TenantID  string `json:"tenantId"`
+	IsDefault bool   `json:"isDefault"`
+	User      struct {
+		Name string `json:"name"`
+	} `json:"user"`
+}
+
+// azureSubscriptions reads the subscriptions `az login` found, empty when logged out
+func azureSubscriptions() ([]azureSubscription, error) {
+	configDir := os.Getenv("AZURE_CONFIG_DIR")
+	if configDir == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		configDir = filepath.Join(homeDir, ".azure")
+	}
+
+	data, err := os.ReadFile(filepath.Join(configDir, "azureProfile.json"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// The az CLI writes the file with a UTF-8 byte order mark
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	var profile struct {
+		Subscriptions []azureSubscription `json:"subscriptions"`
+	}
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return nil, err
+	}
+	return profile.Subscriptions, nil
+}
+
+// subscription returns the subscription ID commands use: the environment's mapping,
+// then the one picked after logging in, "" for the az CLI's default
+func (Azure) subscription(ctx Context) string {
+	if mapping, ok := ctx.Config.AzureMappingFor(ctx.ProjectPath, ctx.EnvName); ok && mapping.Subscription != "" {
+		return mapping.Subscription
+	}
+	return ctx.Picked["azure"]
+}
+
+// Status reports the user and subscription commands use
+func (p Azure) Status(ctx Context) Status {
+	if os.Getenv("ARM_CLIENT_ID") != "" || os.Getenv("ARM_USE_MSI") == "true" {
+		return Status{LoggedIn: true, Text: "service principal from the environment"}
+	}
+	subscriptions, err := azureSubscriptions()
+	if err != nil || len(subscriptions) == 0 {
+		return Status{Text: "not logged in"}
+	}
+
+	wanted := p.subscription(ctx)
+	for _, subscription := range subscriptions {
+		if subscription.ID == wanted || (wanted == "" && subscription.IsDefault) {
+			return Status{LoggedIn: true, Text: subscription.User.Name + " (" + subscription.Name + ")"}
+		}
+	}
+	if wanted != "" {
+		return Status{Text: "subscription " + wanted + " not available"}
+	}
+	return Status{LoggedIn: true, Text: subscriptions[0].User.Name}
+}
+
+// Logins returns `az login`, in the environment's tenant when mapped
+func (Azure) Logins(ctx Context) []Login {
+	login := Login{Label: "az login", Command: "az", Args: []string{"login"}}
+	if mapping, ok := ctx.Config.AzureMappingFor(ctx.ProjectPath, ctx.EnvName); ok && mapping.Tenant != "" {
+		login.Args = append(login.Args, "--tenant", mapping.Tenant)
+	}
+	if ctx.NoBrowser {
+		login.Args = append(login.Args, "--use-device-code")
+		login.DeviceCode = true
+	}
+	return []Login{login}
+}
+
+// Contexts lists the subscriptions of the logged in user, none when the
+// environment's subscription is set in the config
+func (Azure) Contexts(ctx Context) ([]Choice, error) {
+	if mapping, ok := ctx.Config.AzureMappingFor(ctx.ProjectPath, ctx.EnvName); ok && mapping.Subscription != "" {
+		return nil, nil
+	}
+	subscriptions, err := azureSubscriptions()
+	if err != nil {
+		return nil, err
+	}
+	var choices []Choice
+	for _, subscription := range subscriptions {
+		label := subscription.Name + " (" + subscription.ID + ")"
+		if subscription.IsDefault {
+			label += " — az default"
+		}
+		choices = append(choices, Choice{ID: subscription.ID, Label: label})
+	}
+	return choices, nil
+}
+
+// Env passes the environment's subscription and tenant
+func (p Azure) Env(ctx Context) []string {
+	var env []string
+	if subscription := p.subscription(ctx); subscription != "" {
+		env = append(env, "ARM_SUBSCRIPTION_ID="+subscription)
+	}
+	if mapping, ok := ctx.Config.AzureMappingFor(ctx.ProjectPath, ctx.EnvName); ok && mapping.Tenant != "" {
+		env = append(env, "ARM_TENANT_ID="+mapping.Tenant)
+	}
+	return env
+}
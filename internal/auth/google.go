@@ -0,0 +1,131 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/Nicolas-Rigaudy/lazytf/internal/aws"
+)
+
+// Google logs in with `gcloud auth application-default login` (the credentials
+// Terraform uses) or `gcloud auth login` (the gcloud CLI account)
+type Google struct{}
+
+func (Google) ID() string   { return "google" }
+func (Google) Name() string { return "Google Cloud" }
+
+// gcloudConfigDir returns $CLOUDSDK_CONFIG or the default gcloud config directory
+func gcloudConfigDir() (string, error) {
+	if dir := os.Getenv("CLOUDSDK_CONFIG"); dir != "" {
+		return dir, nil
+	}
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("APPDATA"), "gcloud"), nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".config", "gcloud"), nil
+}
+
+// gcloudCoreSettings reads the account and project of the active gcloud configuration
+func gcloudCoreSettings(configDir string) (string, string) {
+	name := os.Getenv("CLOUDSDK_ACTIVE_CONFIG_NAME")
+	if name == "" {
+		data, err := os.ReadFile(filepath.Join(configDir, "active_config"))
+		name = strings.TrimSpace(string(data))
+		if err != nil || name == "" {
+			name = "default"
+		}
+	}
+
+	// gcloud configurations use the same INI syntax as the AWS config file
+	file, err := aws.ParseINIFile(filepath.Join(configDir, "configurations", "config_"+name))
+	if err != nil {
+		return "", ""
+	}
+	core := file.Section("core")
+	if core == nil {
+		return "", ""
+	}
+	return core.Get("account"), core.Get("project")
+}
+
+// applicationDefaultCredentials returns the path of the credentials Terraform's google provider uses
+func applicationDefaultCredentials(configDir string) string {
+	if path := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS"); path != "" {
+		return path
+	}
+	return filepath.Join(configDir, "application_default_credentials.json")
+}
+
+// Status reports the account and project commands use; Terraform needs application default credentials
+func (Google) Status(ctx Context) Status {
+	if os.Getenv("GOOGLE_CREDENTIALS") != "" || os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN") != "" {
+		return Status{LoggedIn: true, Text: "credentials from the environment"}
+	}
+	configDir, err := gcloudConfigDir()
+	if err != nil {
+		return Status{Text: "not logged in"}
+	}
+	account, project := gcloudCoreSettings(configDir)
+	if mapping, ok := ctx.Config.GoogleMappingFor(ctx.ProjectPath, ctx.EnvName); ok {
+		if mapping.Account != "" {
+			account = mapping.Account
+		}
+		if mapping.Project != "" {
+			project = mapping.Project
+		}
+	}
+
+	if _, err := os.Stat(applicationDefaultCredentials(configDir)); err != nil {
+		return Status{Text: "no application default credentials"}
+	}
+	text := account
+	if text == "" {
+		text = "application default credentials"
+	}
+	if project != "" {
+		text += " (" + project + ")"
+	}
+	return Status{LoggedIn: true, Text: text}
+}
+
+// Logins offers application default credentials first, as that is what Terraform uses
+// gcloud's no-browser flows need a code pasted back into the command, which commands
+// started here can't read, so both always open a browser
+func (Google) Logins(ctx Context) []Login {
+	adc := Login{
+		Label:   "gcloud auth application-default login (used by Terraform)",
+		Command: "gcloud",
+		Args:    []string{"auth", "application-default", "login"},
+	}
+	cli := Login{
+		Label:   "gcloud auth login (gcloud CLI account)",
+		Command: "gcloud",
+		Args:    []string{"auth", "login"},
+	}
+	if mapping, ok := ctx.Config.GoogleMappingFor(ctx.ProjectPath, ctx.EnvName); ok && mapping.Account != "" {
+		cli.Args = append(cli.Args, mapping.Account)
+	}
+	return []Login{adc, cli}
+}
+
+// Env passes the environment's project and account
+func (Google) Env(ctx Context) []string {
+	mapping, ok := ctx.Config.GoogleMappingFor(ctx.ProjectPath, ctx.EnvName)
+	if !ok {
+		return nil
+	}
+	var env []string
+	if mapping.Project != "" {
+		env = append(env, "GOOGLE_PROJECT="+mapping.Project, "CLOUDSDK_CORE_PROJECT="+mapping.Project)
+	}
+	if mapping.Account != "" {
+		env = append(env, "CLOUDSDK_CORE_ACCOUNT="+mapping.Account)
+	}
+	return env
+}
@@ -0,0 +1,168 @@
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SSOAccountRole is a role the SSO user can assume in an account
+type SSOAccountRole struct {
+	AccountID   string
+	AccountName string
+	RoleName    string
+}
+
+// ProfileName suggests a profile name, e.g. "my-account-adminaccess-123456789012"
+// The account ID keeps the names of accounts sharing a name apart
+func (r SSOAccountRole) ProfileName() string {
+	name := strings.ToLower(r.AccountName + "-" + r.RoleName + "-" + r.AccountID)
+	name = profileNameCleanup.ReplaceAllString(name, "-")
+	return strings.Trim(name, "-")
+}
+
+var profileNameCleanup = regexp.MustCompile(`[^a-z0-9_.]+`)
+
+// ListSSOAccountRoles lists every account and role the session's cached token gives access to
+// using `aws sso list-accounts` and `aws sso list-account-roles`
+func ListSSOAccountRoles(session *SSOSession) ([]SSOAccountRole, error) {
+	token, err := FindSSOToken(session)
+	if err != nil {
+		return nil, err
+	}
+	if token == nil || token.Expired() {
+		return nil, fmt.Errorf("the SSO session %q is %s, log in first", session.Name, FormatTimeToExpiry(token))
+	}
+	region := session.Region
+	if region == "" {
+		region = token.Region
+	}
+
+	var accounts struct {
+		AccountList []struct {
+			AccountID   string `json:"accountId"`
+			AccountName string `json:"accountName"`
+		} `json:"accountList"`
+	}
+	if err := runSSOCommand(&accounts, "list-accounts", "--access-token", token.accessToken, "--region", region); err != nil {
+		return nil, err
+	}
+
+	var roles []SSOAccountRole
+	for _, account := range accounts.AccountList {
+		var accountRoles struct {
+			RoleList []struct {
+				RoleName string `json:"roleName"`
+			} `json:"roleList"`
+		}
+		if err := runSSOCommand(&accountRoles, "list-account-roles", "--access-token", token.accessToken,
+			"--account-id", account.AccountID, "--region", region); err != nil {
+			return nil, err
+		}
+		for _, role := range accountRoles.RoleList {
+			roles = append(roles, SSOAccountRole{
+				AccountID:   account.AccountID,
+				AccountName: account.AccountName,
+				RoleName:    role.RoleName,
+			})
+		}
+	}
+
+	sort.Slice(roles, func(i, j int) bool {
+		if roles[i].AccountName != roles[j].AccountName {
+			return roles[i].AccountName < roles[j].AccountName
+		}
+		return roles[i].RoleName < roles[j].RoleName
+	})
+	return roles, nil
+}
+
+// runSSOCommand runs `aws sso <args> --output json` and decodes its output into result
+func runSSOCommand(result interface{}, args ...string) error {
+	cmd := exec.Command("aws", append(append([]string{"sso"}, args...), "--output", "json")...)
+	output, err := cmd.Output()
+	if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+		return fmt.Errorf("aws sso %s failed: %s", args[0], strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(output, result)
+}
+
+// AddSSOProfiles appends a [profile ...] section per role to the shared config file,
+// leaving the existing content and comments untouched
+// Profiles that already exist are skipped; the names of the added profiles are returned
+func AddSSOProfiles(session *SSOSession, roles []SSOAccountRole, region string) ([]string, error) {
+	configPath, err := ConfigFilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(configPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	existing := make(map[string]bool)
+	if len(content) > 0 {
+		config, err := ParseINIFile(configPath)
+		if err != nil {
+			return nil, err
+		}
+		profiles := make(map[string]*Profile)
+		addProfiles(config, true, profiles)
+		for name := range profiles {
+			existing[name] = true
+		}
+	}
+
+	var added []string
+	var b strings.Builder
+	b.Write(content)
+	if len(content) > 0 && !strings.HasSuffix(string(content), "\n") {
+		b.WriteString("\n")
+	}
+	for _, role := range roles {
+		name := role.ProfileName()
+		if existing[name] {
+			continue
+		}
+		existing[name] = true
+		added = append(added, name)
+
+		b.WriteString("\n[profile " + name + "]\n")
+		b.WriteString("sso_session = " + session.Name + "\n")
+		b.WriteString("sso_account_id = " + role.AccountID + "\n")
+		b.WriteString("sso_role_name = " + role.RoleName + "\n")
+		if region != "" {
+			b.WriteString("region = " + region + "\n")
+		}
+	}
+	if len(added) == 0 {
+		return nil, nil
+	}
+
+	// Write to a temporary file first so a failure can't leave a truncated config
+	mode := os.FileMode(0600)
+	if info, err := os.Stat(configPath); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.MkdirAll(filepath.Dir(configPath), 0700); err != nil {
+		return nil, err
+	}
+	tmpPath := configPath + ".lazytf.tmp"
+	if err := os.WriteFile(tmpPath, []byte(b.String()), mode); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmpPath, configPath); err != nil {
+		os.Remove(tmpPath)
+		return nil, err
+	}
+	return added, nil
+}
@@ -0,0 +1,73 @@
+package aws
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestProfileName(t *testing.T) {
+	tests := []struct {
+		name string
+		role SSOAccountRole
+		want string
+	}{
+		{"simple", SSOAccountRole{AccountID: "123456789012", AccountName: "prod", RoleName: "Admin"}, "prod-admin-123456789012"},
+		{"same name, other account", SSOAccountRole{AccountID: "210987654321", AccountName: "prod", RoleName: "Admin"}, "prod-admin-210987654321"},
+		{"spaces and symbols", SSOAccountRole{AccountID: "123456789012", AccountName: "My Account (EU)", RoleName: "AWSReadOnlyAccess"}, "my-account-eu-awsreadonlyaccess-123456789012"},
+		{"dots and underscores kept", SSOAccountRole{AccountID: "123456789012", AccountName: "team_a.prod", RoleName: "Ops"}, "team_a.prod-ops-123456789012"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.role.ProfileName(); got != tt.want {
+				t.Errorf("ProfileName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddSSOProfiles(t *testing.T) {
+	configPath := writeINI(t, "# my settings\n[profile prod-admin-123456789012]\nregion = eu-west-1")
+	t.Setenv("AWS_CONFIG_FILE", configPath)
+
+	session := &SSOSession{Name: "corp"}
+	roles := []SSOAccountRole{
+		{AccountID: "123456789012", AccountName: "prod", RoleName: "Admin"},
+		{AccountID: "210987654321", AccountName: "prod", RoleName: "Admin"},
+	}
+	added, err := AddSSOProfiles(session, roles, "eu-west-3")
+	if err != nil {
+		t.Fatalf("AddSSOProfiles() error = %v", err)
+	}
+	if want := []string{"prod-admin-210987654321"}; !reflect.DeepEqual(added, want) {
+		t.Errorf("AddSSOProfiles() added %v, want %v", added, want)
+	}
+
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# my settings\n[profile prod-admin-123456789012]\nregion = eu-west-1\n" +
+		"\n[profile prod-admin-210987654321]\nsso_session = corp\nsso_account_id = 210987654321\nsso_role_name = Admin\nregion = eu-west-3\n"
+	if string(content) != want {
+		t.Errorf("config file =\n%s\nwant\n%s", content, want)
+	}
+
+	// Nothing new to add leaves the file alone
+	if added, err := AddSSOProfiles(session, roles, ""); err != nil || added != nil {
+		t.Errorf("AddSSOProfiles() again = %v, %v, want nothing added", added, err)
+	}
+
+	// A missing config file is created
+	newPath := filepath.Join(t.TempDir(), "aws", "config")
+	t.Setenv("AWS_CONFIG_FILE", newPath)
+	if _, err := AddSSOProfiles(session, roles[:1], ""); err != nil {
+		t.Fatalf("AddSSOProfiles() on a missing file error = %v", err)
+	}
+	if content, _ := os.ReadFile(newPath); !strings.HasPrefix(string(content), "\n[profile prod-admin-123456789012]\n") {
+		t.Errorf("new config file =\n%s", content)
+	}
+}
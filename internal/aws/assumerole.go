@@ -0,0 +1,201 @@
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RoleCredentials are temporary credentials of an assumed role
+type RoleCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Expiration      time.Time
+	Region          string // region of the profile they were obtained for
+}
+
+// Expired reports whether the credentials expire within the next minute,
+// too soon to start a command with them
+func (c *RoleCredentials) Expired() bool {
+	return time.Until(c.Expiration) < time.Minute
+}
+
+// Env returns the credentials as environment variables, in place of AWS_PROFILE
+func (c *RoleCredentials) Env() []string {
+	return []string{
+		"AWS_ACCESS_KEY_ID=" + c.AccessKeyID,
+		"AWS_SECRET_ACCESS_KEY=" + c.SecretAccessKey,
+		"AWS_SESSION_TOKEN=" + c.SessionToken,
+	}
+}
+
+// FormatExpiry describes how long the credentials stay valid, e.g. "52m left"
+func (c *RoleCredentials) FormatExpiry() string {
+	return FormatTimeToExpiry(&SSOToken{ExpiresAt: c.Expiration})
+}
+
+// RoleChain returns the assume-role profiles a profile's credentials go through, from the
+// profile itself down its source_profile chain, ending with the hop closest to the source
+// credentials. Profiles without role_arn end the chain; so do loops and missing profiles.
+func RoleChain(profiles []*Profile, profile *Profile) []*Profile {
+	var chain []*Profile
+	seen := make(map[string]bool)
+	for profile != nil && profile.RoleARN != "" && !seen[profile.Name] {
+		seen[profile.Name] = true
+		chain = append(chain, profile)
+		// source_profile pointing at itself means the profile's own static keys
+		if profile.SourceProfile == "" || profile.SourceProfile == profile.Name {
+			break
+		}
+		profile = FindProfile(profiles, profile.SourceProfile)
+	}
+	return chain
+}
+
+// MFAHop returns the hop of a profile's role chain that needs an MFA code: the first one
+// assumed, starting from the source credentials, with an mfa_serial. nil if there is none.
+func MFAHop(profiles []*Profile, profile *Profile) *Profile {
+	chain := RoleChain(profiles, profile)
+	for i := len(chain) - 1; i >= 0; i-- {
+		if chain[i].MFASerial != "" {
+			return chain[i]
+		}
+	}
+	return nil
+}
+
+// NeedsMFA reports whether a profile assumes a role with an MFA device, directly or through
+// its source_profile chain, which the AWS CLI and Terraform would prompt for on a terminal
+// they don't have here
+func NeedsMFA(profiles []*Profile, profile *Profile) bool {
+	hop := MFAHop(profiles, profile)
+	return hop != nil && (hop.SourceProfile != "" || hop.CredentialSource != "")
+}
+
+var mfaCodePattern = regexp.MustCompile(`^\d{6}$`)
+
+// ValidMFACode reports whether code looks like a TOTP code
+func ValidMFACode(code string) bool {
+	return mfaCodePattern.MatchString(code)
+}
+
+// AssumeRoleWithMFA assumes the roles of a profile's chain with the given MFA code: the
+// MFA hop with the credentials of its source_profile (or credential_source), then each
+// remaining hop up to the profile with the credentials of the previous one
+func AssumeRoleWithMFA(profiles []*Profile, profile *Profile, code string) (*RoleCredentials, error) {
+	chain := RoleChain(profiles, profile)
+	hop := MFAHop(profiles, profile)
+	if hop == nil {
+		return nil, fmt.Errorf("profile %s doesn't assume a role with MFA", profile.Name)
+	}
+
+	var credentials *RoleCredentials
+	started := false
+	for i := len(chain) - 1; i >= 0; i-- {
+		current := chain[i]
+		if current == hop {
+			started = true
+		}
+		if !started {
+			continue
+		}
+
+		var err error
+		if credentials == nil {
+			credentials, err = assumeRole(current, profile.Region, nil,
+				"--serial-number", current.MFASerial, "--token-code", code)
+		} else {
+			credentials, err = assumeRole(current, profile.Region, credentials)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return credentials, nil
+}
+
+// assumeRole runs `aws sts assume-role` for one hop of a role chain, with the given
+// credentials or, when nil, the hop's source_profile (or credential_source)
+func assumeRole(profile *Profile, region string, source *RoleCredentials, extraArgs ...string) (*RoleCredentials, error) {
+	sessionName := profile.RoleSessionName
+	if sessionName == "" {
+		sessionName = "lazytf-" + strconv.FormatInt(time.Now().Unix(), 10)
+	}
+	args := []string{"sts", "assume-role",
+		"--role-arn", profile.RoleARN,
+		"--role-session-name", sessionName,
+		"--output", "json",
+	}
+	args = append(args, extraArgs...)
+	if profile.DurationSeconds > 0 {
+		args = append(args, "--duration-seconds", strconv.Itoa(profile.DurationSeconds))
+	}
+	if profile.ExternalID != "" {
+		args = append(args, "--external-id", profile.ExternalID)
+	}
+	if source == nil && profile.SourceProfile != "" {
+		args = append(args, "--profile", profile.SourceProfile)
+	}
+	if profile.Region != "" {
+		region = profile.Region
+	}
+	if region != "" {
+		args = append(args, "--region", region)
+	}
+
+	cmd := exec.Command("aws", args...)
+	// The source credentials come from --profile, the previous hop or the environment for
+	// credential_source; an inherited AWS_PROFILE pointing at the role profile would make
+	// the CLI prompt itself
+	for _, entry := range os.Environ() {
+		if strings.HasPrefix(entry, "AWS_PROFILE=") {
+			continue
+		}
+		if source != nil && (strings.HasPrefix(entry, "AWS_ACCESS_KEY_ID=") ||
+			strings.HasPrefix(entry, "AWS_SECRET_ACCESS_KEY=") || strings.HasPrefix(entry, "AWS_SESSION_TOKEN=")) {
+			continue
+		}
+		cmd.Env = append(cmd.Env, entry)
+	}
+	if source != nil {
+		cmd.Env = append(cmd.Env, source.Env()...)
+	}
+
+	output, err := cmd.Output()
+	if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+		return nil, fmt.Errorf("aws sts assume-role %s failed: %s", profile.RoleARN, strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Credentials struct {
+			AccessKeyID     string `json:"AccessKeyId"`
+			SecretAccessKey string `json:"SecretAccessKey"`
+			SessionToken    string `json:"SessionToken"`
+			Expiration      string `json:"Expiration"`
+		} `json:"Credentials"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, fmt.Errorf("unexpected assume-role output: %v", err)
+	}
+	expiration, err := time.Parse(time.RFC3339, result.Credentials.Expiration)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected assume-role expiration %q", result.Credentials.Expiration)
+	}
+
+	return &RoleCredentials{
+		AccessKeyID:     result.Credentials.AccessKeyID,
+		SecretAccessKey: result.Credentials.SecretAccessKey,
+		SessionToken:    result.Credentials.SessionToken,
+		Expiration:      expiration,
+		Region:          region,
+	}, nil
+}
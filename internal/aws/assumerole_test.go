@@ -0,0 +1,60 @@
+package aws
+
+import "testing"
+
+func TestMFAHop(t *testing.T) {
+	profiles := []*Profile{
+		{Name: "base", StaticKeys: true},
+		{Name: "mfa", RoleARN: "arn:aws:iam::111111111111:role/Jump", SourceProfile: "base", MFASerial: "arn:aws:iam::111111111111:mfa/me"},
+		{Name: "target", RoleARN: "arn:aws:iam::222222222222:role/Admin", SourceProfile: "mfa"},
+		{Name: "no-mfa", RoleARN: "arn:aws:iam::222222222222:role/ReadOnly", SourceProfile: "base"},
+		{Name: "instance", RoleARN: "arn:aws:iam::222222222222:role/Ops", CredentialSource: "Ec2InstanceMetadata", MFASerial: "arn:aws:iam::111111111111:mfa/me"},
+		{Name: "self", RoleARN: "arn:aws:iam::222222222222:role/Self", SourceProfile: "self", MFASerial: "arn:aws:iam::111111111111:mfa/me", StaticKeys: true},
+		{Name: "loop-a", RoleARN: "arn:aws:iam::222222222222:role/A", SourceProfile: "loop-b", MFASerial: "arn:aws:iam::111111111111:mfa/me"},
+		{Name: "loop-b", RoleARN: "arn:aws:iam::222222222222:role/B", SourceProfile: "loop-a"},
+		{Name: "dangling", RoleARN: "arn:aws:iam::222222222222:role/D", SourceProfile: "missing"},
+	}
+
+	tests := []struct {
+		profile  string
+		chain    int
+		hop      string // "" when no hop needs MFA
+		needsMFA bool
+	}{
+		{profile: "base", chain: 0},
+		{profile: "mfa", chain: 1, hop: "mfa", needsMFA: true},
+		{profile: "target", chain: 2, hop: "mfa", needsMFA: true},
+		{profile: "no-mfa", chain: 1},
+		{profile: "instance", chain: 1, hop: "instance", needsMFA: true},
+		{profile: "self", chain: 1, hop: "self", needsMFA: true},
+		{profile: "loop-a", chain: 2, hop: "loop-a", needsMFA: true},
+		{profile: "dangling", chain: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.profile, func(t *testing.T) {
+			profile := FindProfile(profiles, tt.profile)
+			if chain := RoleChain(profiles, profile); len(chain) != tt.chain {
+				t.Errorf("RoleChain() has %d hops, want %d", len(chain), tt.chain)
+			}
+			hop := ""
+			if found := MFAHop(profiles, profile); found != nil {
+				hop = found.Name
+			}
+			if hop != tt.hop {
+				t.Errorf("MFAHop() = %q, want %q", hop, tt.hop)
+			}
+			if got := NeedsMFA(profiles, profile); got != tt.needsMFA {
+				t.Errorf("NeedsMFA() = %v, want %v", got, tt.needsMFA)
+			}
+		})
+	}
+}
+
+func TestValidMFACode(t *testing.T) {
+	for code, want := range map[string]bool{"123456": true, "12345": false, "1234567": false, "12a456": false, "": false} {
+		if got := ValidMFACode(code); got != want {
+			t.Errorf("ValidMFACode(%q) = %v, want %v", code, got, want)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// CallerIdentity is the result of `aws sts get-caller-identity`
+type CallerIdentity struct {
+	Account string `json:"Account"`
+	Arn     string `json:"Arn"`
+	UserID  string `json:"UserId"`
+}
+
+// GetCallerIdentity resolves the identity commands run as, with env added to the
+// current environment (e.g. AWS_PROFILE=dev)
+func GetCallerIdentity(env []string) (*CallerIdentity, error) {
+	cmd := exec.Command("aws", "sts", "get-caller-identity", "--output", "json")
+	cmd.Env = append(os.Environ(), env...)
+
+	output, err := cmd.Output()
+	if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+		return nil, fmt.Errorf("aws sts get-caller-identity failed: %s", strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var identity CallerIdentity
+	if err := json.Unmarshal(output, &identity); err != nil {
+		return nil, fmt.Errorf("unexpected get-caller-identity output: %v", err)
+	}
+	return &identity, nil
+}
@@ -0,0 +1,186 @@
+package aws
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Nicolas-Rigaudy/lazytf/internal/config"
+)
+
+// INIFile is a parsed AWS shared config or credentials file
+type INIFile struct {
+	Path     string
+	Sections []*INISection
+}
+
+// INISection is a [section] of an INI file, e.g. [profile dev] or [sso-session corp]
+type INISection struct {
+	Name string // text between the brackets, e.g. "profile dev"
+	Line int    // 1-based line of the section header
+	Keys []*INIKey
+}
+
+// INIKey is a key = value line
+// Keys with an empty value followed by indented lines form a sub-section, e.g.
+//
+//	s3 =
+//	  max_concurrent_requests = 20
+type INIKey struct {
+	Name    string
+	Value   string
+	Line    int
+	SubKeys []*INIKey
+}
+
+// INIError is a syntax error at a given line
+type INIError struct {
+	Path string
+	Line int
+	Msg  string
+}
+
+func (e *INIError) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.Path, e.Line, e.Msg)
+}
+
+// ConfigFilePath returns the shared config file: $AWS_CONFIG_FILE or ~/.aws/config
+func ConfigFilePath() (string, error) {
+	if path := os.Getenv("AWS_CONFIG_FILE"); path != "" {
+		return config.ExpandHome(path)
+	}
+	return awsConfigPath("config")
+}
+
+// CredentialsFilePath returns the shared credentials file: $AWS_SHARED_CREDENTIALS_FILE or ~/.aws/credentials
+func CredentialsFilePath() (string, error) {
+	if path := os.Getenv("AWS_SHARED_CREDENTIALS_FILE"); path != "" {
+		return config.ExpandHome(path)
+	}
+	return awsConfigPath("credentials")
+}
+
+// ParseINIFile reads and parses an INI file
+// Comments start with # or ; at the start of a line, or after whitespace within a value
+// Indented lines continue the previous value, or form a sub-section after an empty value;
+// indentation is ignored for section headers and for the first key of a section
+func ParseINIFile(path string) (*INIFile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	ini := &INIFile{Path: path}
+	var section *INISection
+	var lastKey *INIKey
+
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		raw := scanner.Text()
+		line := strings.TrimSpace(raw)
+
+		// Skip empty lines and comments
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		indented := raw[0] == ' ' || raw[0] == '\t'
+
+		if strings.HasPrefix(line, "[") {
+			end := strings.Index(line, "]")
+			if end < 0 {
+				return nil, &INIError{Path: path, Line: lineNumber, Msg: "section header is missing ']'"}
+			}
+			section = &INISection{Name: strings.TrimSpace(line[1:end]), Line: lineNumber}
+			ini.Sections = append(ini.Sections, section)
+			lastKey = nil
+			continue
+		}
+
+		if section == nil {
+			return nil, &INIError{Path: path, Line: lineNumber, Msg: "key outside of any section"}
+		}
+
+		// Indented line: sub-section entry or continuation of the previous value
+		if indented && lastKey != nil {
+			if lastKey.Value == "" || len(lastKey.SubKeys) > 0 {
+				name, value, ok := splitINIKey(line)
+				if !ok {
+					return nil, &INIError{Path: path, Line: lineNumber, Msg: "expected 'key = value' in sub-section " + lastKey.Name}
+				}
+				lastKey.SubKeys = append(lastKey.SubKeys, &INIKey{Name: name, Value: value, Line: lineNumber})
+			} else {
+				lastKey.Value += "\n" + stripInlineComment(line)
+			}
+			continue
+		}
+
+		name, value, ok := splitINIKey(line)
+		if !ok {
+			return nil, &INIError{Path: path, Line: lineNumber, Msg: "expected 'key = value' or '[section]'"}
+		}
+		lastKey = &INIKey{Name: name, Value: value, Line: lineNumber}
+		section.Keys = append(section.Keys, lastKey)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return ini, nil
+}
+
+// splitINIKey splits "key = value # comment" into its key and value
+func splitINIKey(line string) (string, string, bool) {
+	parts := strings.SplitN(line, "=", 2)
+	if len(parts) != 2 {
+		return "", "", false
+	}
+	name := strings.TrimSpace(parts[0])
+	if name == "" {
+		return "", "", false
+	}
+	return name, stripInlineComment(strings.TrimSpace(parts[1])), true
+}
+
+// stripInlineComment removes a trailing " # comment" or " ; comment" from a value
+func stripInlineComment(value string) string {
+	for i := 1; i < len(value); i++ {
+		if (value[i] == '#' || value[i] == ';') && (value[i-1] == ' ' || value[i-1] == '\t') {
+			return strings.TrimSpace(value[:i])
+		}
+	}
+	return value
+}
+
+// Get returns the value of a key in the section, "" if it isn't set
+func (s *INISection) Get(name string) string {
+	if key := s.Key(name); key != nil {
+		return key.Value
+	}
+	return ""
+}
+
+// Key returns a key of the section, nil if it isn't set (the last one wins if repeated)
+func (s *INISection) Key(name string) *INIKey {
+	var found *INIKey
+	for _, key := range s.Keys {
+		if key.Name == name {
+			found = key
+		}
+	}
+	return found
+}
+
+// Section returns the section with the given name, nil if there is none
+func (f *INIFile) Section(name string) *INISection {
+	for _, section := range f.Sections {
+		if section.Name == name {
+			return section
+		}
+	}
+	return nil
+}
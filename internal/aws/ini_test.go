@@ -0,0 +1,150 @@
+package aws
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeINI writes content to a temporary file and returns its path
+func writeINI(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestParseINIFile(t *testing.T) {
+	path := writeINI(t, `# comment
+[default]
+region = eu-west-1 # inline comment
+output=json
+
+  [profile dev]
+  sso_session = corp
+sso_account_id = 123456789012
+; another comment
+s3 =
+  max_concurrent_requests = 20
+  addressing_style = path
+ca_bundle = first
+  second
+
+[sso-session corp]
+sso_start_url = https://corp.awsapps.com/start#/
+`)
+
+	file, err := ParseINIFile(path)
+	if err != nil {
+		t.Fatalf("ParseINIFile() error = %v", err)
+	}
+
+	var names []string
+	for _, section := range file.Sections {
+		names = append(names, section.Name)
+	}
+	if want := []string{"default", "profile dev", "sso-session corp"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("sections = %v, want %v", names, want)
+	}
+
+	tests := []struct {
+		section string
+		key     string
+		want    string
+	}{
+		{"default", "region", "eu-west-1"},
+		{"default", "output", "json"},
+		{"profile dev", "sso_session", "corp"},
+		{"profile dev", "sso_account_id", "123456789012"},
+		{"profile dev", "ca_bundle", "first\nsecond"},
+		{"sso-session corp", "sso_start_url", "https://corp.awsapps.com/start#/"},
+		{"default", "missing", ""},
+	}
+	for _, tt := range tests {
+		if got := file.Section(tt.section).Get(tt.key); got != tt.want {
+			t.Errorf("[%s] %s = %q, want %q", tt.section, tt.key, got, tt.want)
+		}
+	}
+
+	s3 := file.Section("profile dev").Key("s3")
+	if s3 == nil || len(s3.SubKeys) != 2 || s3.SubKeys[0].Name != "max_concurrent_requests" || s3.SubKeys[1].Value != "path" {
+		t.Errorf("s3 sub-section = %+v, want max_concurrent_requests and addressing_style", s3)
+	}
+	if line := file.Section("profile dev").Line; line != 6 {
+		t.Errorf("[profile dev] line = %d, want 6", line)
+	}
+}
+
+func TestParseINIFileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		line    int
+	}{
+		{"unclosed section header", "[default]\nregion = eu-west-1\n[profile dev\n", 3},
+		{"key outside of any section", "region = eu-west-1\n", 1},
+		{"line without '='", "[default]\nregion\n", 2},
+		{"bad sub-section entry", "[default]\ns3 =\n  max_concurrent_requests\n", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeINI(t, tt.content)
+			_, err := ParseINIFile(path)
+			var iniErr *INIError
+			if !errors.As(err, &iniErr) {
+				t.Fatalf("ParseINIFile() error = %v, want an *INIError", err)
+			}
+			if iniErr.Line != tt.line || iniErr.Path != path {
+				t.Errorf("error at %s:%d, want %s:%d", iniErr.Path, iniErr.Line, path, tt.line)
+			}
+		})
+	}
+}
+
+func TestLoadSharedConfig(t *testing.T) {
+	configPath := writeINI(t, `[default]
+region = eu-west-1
+
+[profile dev]
+sso_session = corp
+sso_account_id = 123456789012
+sso_role_name = Admin
+
+[profile ops]
+role_arn = arn:aws:iam::210987654321:role/Ops
+source_profile = default
+mfa_serial = arn:aws:iam::123456789012:mfa/me
+
+[sso-session corp]
+sso_start_url = https://corp.awsapps.com/start
+sso_region = eu-west-1
+`)
+	t.Setenv("AWS_CONFIG_FILE", configPath)
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "missing"))
+
+	shared, err := LoadSharedConfig()
+	if err != nil {
+		t.Fatalf("LoadSharedConfig() error = %v", err)
+	}
+	var kinds []string
+	for _, profile := range shared.Profiles {
+		kinds = append(kinds, profile.Name+":"+profile.Kind())
+	}
+	if want := []string{"default:default chain", "dev:sso", "ops:assume-role"}; !reflect.DeepEqual(kinds, want) {
+		t.Errorf("profiles = %v, want %v", kinds, want)
+	}
+	if len(shared.Sessions) != 1 || shared.Sessions[0].Name != "corp" || shared.Sessions[0].Region != "eu-west-1" {
+		t.Errorf("sessions = %+v, want corp in eu-west-1", shared.Sessions)
+	}
+
+	// Syntax errors are reported with the file and line
+	t.Setenv("AWS_CONFIG_FILE", writeINI(t, "[default\n"))
+	if _, err := LoadSharedConfig(); err == nil {
+		t.Error("LoadSharedConfig() succeeded on a malformed config file")
+	}
+}
@@ -1,13 +1,90 @@
 package aws
 
 import (
-	"github.com/Nicolas-Rigaudy/lazytf/internal/executor"
-	tea "github.com/charmbracelet/bubbletea"
+	"regexp"
+	"strings"
 )
 
-// RunSSOLogin executes `aws sso login --sso-session <sessionName>`
-// This command opens a browser for authentication
-func RunSSOLogin(session *SSOSession) tea.Cmd {
+// LoginMode selects how `aws sso login` authenticates
+type LoginMode string
+
+const (
+	LoginBrowser    LoginMode = "browser"     // open a browser on this machine (default)
+	LoginNoBrowser  LoginMode = "no-browser"  // print a URL to open elsewhere (--no-browser)
+	LoginDeviceCode LoginMode = "device-code" // device code flow, needed with PKCE capable CLIs (--no-browser --use-device-code)
+)
+
+// ParseLoginMode converts a config value to a LoginMode, defaulting to LoginBrowser
+func ParseLoginMode(value string) LoginMode {
+	switch LoginMode(value) {
+	case LoginNoBrowser, LoginDeviceCode:
+		return LoginMode(value)
+	default:
+		return LoginBrowser
+	}
+}
+
+// SSOLoginArgs returns the arguments of `aws sso login --sso-session <sessionName>`
+// In browser mode this command opens a browser for authentication, in the other
+// modes it prints a verification URL and code (see DeviceAuthorization)
+func SSOLoginArgs(session *SSOSession, mode LoginMode) []string {
 	args := []string{"sso", "login", "--sso-session", session.Name}
-	return executor.ExecuteStreaming("aws", args, "")
+	if session.Profile != "" {
+		args = []string{"sso", "login", "--profile", session.Profile}
+	}
+	switch mode {
+	case LoginNoBrowser:
+		args = append(args, "--no-browser")
+	case LoginDeviceCode:
+		args = append(args, "--no-browser", "--use-device-code")
+	}
+	return args
+}
+
+var (
+	loginURLPattern  = regexp.MustCompile(`https://\S+`)
+	loginCodePattern = regexp.MustCompile(`^[A-Z0-9]{4}-[A-Z0-9]{4}$`)
+	// az login --use-device-code gives the code within a sentence
+	inlineCodePattern = regexp.MustCompile(`enter the code ([A-Z0-9-]{6,})`)
+)
+
+// DeviceAuthorization is what a no-browser login asks the user to open and enter
+type DeviceAuthorization struct {
+	URL  string
+	Code string // user code, "" when the URL already contains it or the flow doesn't use one
+}
+
+// ParseLine reads a line of `aws sso login --no-browser` output and reports
+// whether it added the URL or the code
+//
+//	Please visit the following URL:
+//
+//	https://device.sso.eu-west-1.amazonaws.com/
+//
+//	Then enter the code:
+//
+//	ABCD-EFGH
+//
+// `az login --use-device-code` prints both on one line:
+//
+//	To sign in, use a web browser to open the page https://microsoft.com/devicelogin and enter the code ABCD1234E to authenticate.
+func (d *DeviceAuthorization) ParseLine(line string) bool {
+	line = strings.TrimSpace(line)
+	added := false
+	if d.URL == "" {
+		if url := loginURLPattern.FindString(line); url != "" {
+			d.URL = url
+			added = true
+		}
+	}
+	if d.Code == "" {
+		if loginCodePattern.MatchString(line) {
+			d.Code = line
+			added = true
+		} else if match := inlineCodePattern.FindStringSubmatch(line); match != nil {
+			d.Code = match[1]
+			added = true
+		}
+	}
+	return added
 }
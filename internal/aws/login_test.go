@@ -0,0 +1,77 @@
+package aws
+
+import "testing"
+
+func TestDeviceAuthorizationParseLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		lines    []string
+		wantURL  string
+		wantCode string
+	}{
+		{
+			name: "aws sso login --no-browser",
+			lines: []string{
+				"Browser will not be automatically opened.",
+				"Please visit the following URL:",
+				"",
+				"https://device.sso.eu-west-1.amazonaws.com/",
+				"",
+				"Then enter the code:",
+				"",
+				"ABCD-EFGH",
+			},
+			wantURL:  "https://device.sso.eu-west-1.amazonaws.com/",
+			wantCode: "ABCD-EFGH",
+		},
+		{
+			name: "device code in the URL",
+			lines: []string{
+				"Please visit the following URL:",
+				"  https://device.sso.eu-west-1.amazonaws.com/?user_code=ABCD-EFGH  ",
+				"Successfully logged into Start URL: https://corp.awsapps.com/start",
+			},
+			wantURL: "https://device.sso.eu-west-1.amazonaws.com/?user_code=ABCD-EFGH",
+		},
+		{
+			name: "az login --use-device-code",
+			lines: []string{
+				"To sign in, use a web browser to open the page https://microsoft.com/devicelogin and enter the code ABCD1234E to authenticate.",
+			},
+			wantURL:  "https://microsoft.com/devicelogin",
+			wantCode: "ABCD1234E",
+		},
+		{
+			name:  "unrelated output",
+			lines: []string{"Attempting to automatically open the SSO authorization page", "Successfully logged in"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var auth DeviceAuthorization
+			for _, line := range tt.lines {
+				auth.ParseLine(line)
+			}
+			if auth.URL != tt.wantURL || auth.Code != tt.wantCode {
+				t.Errorf("ParseLine() gave URL %q and code %q, want %q and %q", auth.URL, auth.Code, tt.wantURL, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestDeviceAuthorizationParseLineReportsAdditions(t *testing.T) {
+	var auth DeviceAuthorization
+	if auth.ParseLine("Then enter the code:") {
+		t.Error("ParseLine() reported an addition for a line without URL or code")
+	}
+	if !auth.ParseLine("https://device.sso.eu-west-1.amazonaws.com/") {
+		t.Error("ParseLine() didn't report the URL")
+	}
+	if auth.ParseLine("https://other.example.com/") {
+		t.Error("ParseLine() replaced the URL already found")
+	}
+	if !auth.ParseLine("ABCD-EFGH") {
+		t.Error("ParseLine() didn't report the code")
+	}
+}
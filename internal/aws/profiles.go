@@ -0,0 +1,222 @@
+package aws
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Profile represents an AWS named profile from ~/.aws/config or ~/.aws/credentials
+type Profile struct {
+	Name string
+
+	// SSO, either through an [sso-session] or the legacy inline settings
+	SSOSession   string
+	SSOAccountID string
+	SSORoleName  string
+	SSOStartURL  string
+	SSORegion    string
+
+	// Assume role
+	SourceProfile    string
+	RoleARN          string
+	MFASerial        string
+	ExternalID       string
+	RoleSessionName  string
+	CredentialSource string
+	DurationSeconds  int // lifetime of the assumed role session, 0 for the default (1 hour)
+
+	CredentialProcess string
+	StaticKeys        bool // aws_access_key_id is set (credentials file or config)
+
+	Region string
+	Output string
+}
+
+// Kind describes how the profile gets its credentials
+func (p *Profile) Kind() string {
+	switch {
+	case p.RoleARN != "":
+		return "assume-role"
+	case p.SSOSession != "" || p.SSOStartURL != "":
+		return "sso"
+	case p.CredentialProcess != "":
+		return "process"
+	case p.StaticKeys:
+		return "static"
+	default:
+		return "default chain"
+	}
+}
+
+// Description summarizes the profile for the picker, e.g. "sso 123456789012/Admin (eu-west-1)"
+func (p *Profile) Description() string {
+	result := p.Kind()
+	switch p.Kind() {
+	case "sso":
+		result += " " + p.SSOAccountID + "/" + p.SSORoleName
+	case "assume-role":
+		result += " " + p.RoleARN
+		if p.SourceProfile != "" {
+			result += " via " + p.SourceProfile
+		}
+		if p.MFASerial != "" {
+			result += " (MFA)"
+		}
+	}
+	if p.Region != "" {
+		result += " (" + p.Region + ")"
+	}
+	return result
+}
+
+// awsConfigPath returns the path of ~/.aws/<name>
+func awsConfigPath(name string) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".aws", name), nil
+}
+
+// SharedConfig holds everything read from the shared config and credentials files
+type SharedConfig struct {
+	Sessions []*SSOSession
+	Profiles []*Profile // from both files, sorted by name
+}
+
+// LoadSharedConfig parses the shared config ($AWS_CONFIG_FILE or ~/.aws/config) and
+// credentials ($AWS_SHARED_CREDENTIALS_FILE or ~/.aws/credentials) files
+// Missing files are treated as empty, syntax errors are returned with their position
+func LoadSharedConfig() (*SharedConfig, error) {
+	shared := &SharedConfig{}
+	profiles := make(map[string]*Profile)
+
+	configPath, err := ConfigFilePath()
+	if err != nil {
+		return nil, err
+	}
+	config, err := ParseINIFile(configPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read AWS config file: %v", err)
+	}
+	if config != nil {
+		shared.Sessions = ssoSessionsFromINI(config)
+		addProfiles(config, true, profiles)
+	}
+
+	credentialsPath, err := CredentialsFilePath()
+	if err != nil {
+		return nil, err
+	}
+	credentials, err := ParseINIFile(credentialsPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read AWS credentials file: %v", err)
+	}
+	if credentials != nil {
+		addProfiles(credentials, false, profiles)
+	}
+
+	for _, profile := range profiles {
+		shared.Profiles = append(shared.Profiles, profile)
+	}
+	sort.Slice(shared.Profiles, func(i, j int) bool { return shared.Profiles[i].Name < shared.Profiles[j].Name })
+	return shared, nil
+}
+
+// DiscoverProfiles returns the profiles of the shared config and credentials files, sorted by name
+func DiscoverProfiles() ([]*Profile, error) {
+	shared, err := LoadSharedConfig()
+	if err != nil {
+		return nil, err
+	}
+	return shared.Profiles, nil
+}
+
+// SharedFilePaths returns the shared config and credentials files for messages,
+// e.g. "/home/me/.aws/config or /home/me/.aws/credentials"
+func SharedFilePaths() string {
+	configPath, err := ConfigFilePath()
+	if err != nil {
+		configPath = "~/.aws/config"
+	}
+	credentialsPath, err := CredentialsFilePath()
+	if err != nil {
+		credentialsPath = "~/.aws/credentials"
+	}
+	return configPath + " or " + credentialsPath
+}
+
+// FindProfile returns the profile with the given name, or nil if it doesn't exist
+func FindProfile(profiles []*Profile, name string) *Profile {
+	for _, profile := range profiles {
+		if profile.Name == name {
+			return profile
+		}
+	}
+	return nil
+}
+
+// addProfiles adds the profiles of one parsed file to profiles
+// In the config file profiles are [profile name] (except [default]), in the credentials file just [name]
+func addProfiles(file *INIFile, isConfig bool, profiles map[string]*Profile) {
+	for _, section := range file.Sections {
+		name := ""
+		switch {
+		case !isConfig || section.Name == "default":
+			name = section.Name
+		case strings.HasPrefix(section.Name, "profile "):
+			name = strings.TrimSpace(strings.TrimPrefix(section.Name, "profile "))
+		}
+		// Other sections ([sso-session ...], [services ...]) aren't profiles
+		if name == "" {
+			continue
+		}
+
+		current := profiles[name]
+		if current == nil {
+			current = &Profile{Name: name}
+			profiles[name] = current
+		}
+
+		for _, key := range section.Keys {
+			switch key.Name {
+			case "sso_session":
+				current.SSOSession = key.Value
+			case "sso_account_id":
+				current.SSOAccountID = key.Value
+			case "sso_role_name":
+				current.SSORoleName = key.Value
+			case "sso_start_url":
+				current.SSOStartURL = key.Value
+			case "sso_region":
+				current.SSORegion = key.Value
+			case "source_profile":
+				current.SourceProfile = key.Value
+			case "role_arn":
+				current.RoleARN = key.Value
+			case "mfa_serial":
+				current.MFASerial = key.Value
+			case "external_id":
+				current.ExternalID = key.Value
+			case "role_session_name":
+				current.RoleSessionName = key.Value
+			case "credential_source":
+				current.CredentialSource = key.Value
+			case "duration_seconds":
+				current.DurationSeconds, _ = strconv.Atoi(key.Value)
+			case "credential_process":
+				current.CredentialProcess = key.Value
+			case "aws_access_key_id":
+				current.StaticKeys = true
+			case "region":
+				current.Region = key.Value
+			case "output":
+				current.Output = key.Value
+			}
+		}
+	}
+}
@@ -1,10 +1,6 @@
 package aws
 
 import (
-	"bufio"
-	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
 )
 
@@ -14,57 +10,31 @@ type SSOSession struct {
 	StartURL string
 	Region   string
 	Scopes   string
+	Profile  string // set for legacy profiles with inline sso_start_url, logged in with --profile
 }
 
-// DiscoverSSOSessions parses ~/.aws/config and returns all [sso-session ...] entries
+// DiscoverSSOSessions parses the shared config file and returns all [sso-session ...] entries
 func DiscoverSSOSessions() ([]*SSOSession, error) {
-	homeDir, err := os.UserHomeDir()
+	shared, err := LoadSharedConfig()
 	if err != nil {
 		return nil, err
 	}
+	return shared.Sessions, nil
+}
 
-	configPath := filepath.Join(homeDir, ".aws", "config")
-	file, err := os.Open(configPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open AWS config file: %v", err)
-	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
+// ssoSessionsFromINI returns the [sso-session ...] sections of a parsed config file
+func ssoSessionsFromINI(config *INIFile) []*SSOSession {
 	var sessions []*SSOSession
-	var currentSession *SSOSession
-
-	for scanner.Scan() {
-		// Skip empty lines and comments
-		if scanner.Text() == "" || strings.HasPrefix(scanner.Text(), "#") {
+	for _, section := range config.Sections {
+		if !strings.HasPrefix(section.Name, "sso-session ") {
 			continue
 		}
-		line := strings.TrimSpace(scanner.Text())
-		if strings.HasPrefix(line, "[sso-session ") && strings.HasSuffix(line, "]") {
-			// Start a new session, and save its name to the slice
-			sessionName := strings.TrimSuffix(strings.TrimPrefix(line, "[sso-session "), "]")
-			currentSession = &SSOSession{Name: sessionName}
-			sessions = append(sessions, currentSession)
-
-		} else if currentSession != nil && strings.Contains(line, "=") {
-			// Parse key-value pairs within the current session
-			parts := strings.SplitN(line, "=", 2)
-			key := strings.TrimSpace(parts[0])
-			value := strings.TrimSpace(parts[1])
-			switch key {
-			case "sso_start_url":
-				currentSession.StartURL = value
-			case "sso_region":
-				currentSession.Region = value
-			case "sso_registration_scopes":
-				currentSession.Scopes = value
-			}
-		}
+		sessions = append(sessions, &SSOSession{
+			Name:     strings.TrimSpace(strings.TrimPrefix(section.Name, "sso-session ")),
+			StartURL: section.Get("sso_start_url"),
+			Region:   section.Get("sso_region"),
+			Scopes:   section.Get("sso_registration_scopes"),
+		})
 	}
-
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading AWS config file: %v", err)
-	}
-
-	return sessions, nil
+	return sessions
 }
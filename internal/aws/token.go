@@ -0,0 +1,173 @@
+package aws
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// SSOToken is an access token cached by `aws sso login` in ~/.aws/sso/cache
+// The token itself is only kept unexported, to call the SSO API through the CLI
+type SSOToken struct {
+	StartURL  string
+	Region    string
+	ExpiresAt time.Time
+	File      string
+
+	accessToken string // only passed to the AWS CLI, never displayed
+}
+
+// Expired reports whether the token can no longer be used
+func (t *SSOToken) Expired() bool {
+	return !time.Now().Before(t.ExpiresAt)
+}
+
+// ssoCacheFile is the JSON layout of a token cache file
+// Client registration files live in the same directory but have no accessToken
+type ssoCacheFile struct {
+	StartURL    string `json:"startUrl"`
+	Region      string `json:"region"`
+	AccessToken string `json:"accessToken"`
+	ExpiresAt   string `json:"expiresAt"`
+}
+
+// FindSSOToken returns the cached token of an SSO session, or nil if there is none
+// The AWS CLI names cache files after the SHA1 of the session name (or of the start URL
+// for legacy profiles); other files are checked by start URL in case the name changed
+// When several tokens match, the one expiring last is returned
+func FindSSOToken(session *SSOSession) (*SSOToken, error) {
+	cacheDir, err := awsConfigPath(filepath.Join("sso", "cache"))
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(cacheDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cacheKey := session.Name
+	if session.Profile != "" {
+		cacheKey = session.StartURL
+	}
+	sum := sha1.Sum([]byte(cacheKey))
+	expectedFile := hex.EncodeToString(sum[:]) + ".json"
+
+	var best *SSOToken
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		token := readSSOToken(filepath.Join(cacheDir, entry.Name()))
+		if token == nil {
+			continue
+		}
+		if entry.Name() != expectedFile && (session.StartURL == "" || token.StartURL != session.StartURL) {
+			continue
+		}
+		if best == nil || token.ExpiresAt.After(best.ExpiresAt) {
+			best = token
+		}
+	}
+	return best, nil
+}
+
+// recentTokens caches FindSSOToken results for a few seconds, so the header
+// doesn't read the cache directory on every render
+var recentTokens = struct {
+	sync.Mutex
+	entries map[string]recentToken
+}{entries: make(map[string]recentToken)}
+
+type recentToken struct {
+	token  *SSOToken
+	readAt time.Time
+}
+
+// RecentSSOToken is FindSSOToken with results reused for up to 10 seconds
+func RecentSSOToken(session *SSOSession) *SSOToken {
+	key := session.Name + "|" + session.StartURL
+	recentTokens.Lock()
+	defer recentTokens.Unlock()
+
+	if entry, ok := recentTokens.entries[key]; ok && time.Since(entry.readAt) < 10*time.Second {
+		return entry.token
+	}
+	token, _ := FindSSOToken(session)
+	recentTokens.entries[key] = recentToken{token: token, readAt: time.Now()}
+	return token
+}
+
+// readSSOToken reads a cache file, returning nil if it isn't a token
+func readSSOToken(path string) *SSOToken {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var cached ssoCacheFile
+	if err := json.Unmarshal(data, &cached); err != nil || cached.AccessToken == "" {
+		return nil
+	}
+
+	// Recent CLIs write RFC 3339, older ones "2006-01-02T15:04:05UTC"
+	expiresAt, err := time.Parse(time.RFC3339, cached.ExpiresAt)
+	if err != nil {
+		expiresAt, err = time.Parse("2006-01-02T15:04:05UTC", cached.ExpiresAt)
+		if err != nil {
+			return nil
+		}
+	}
+
+	return &SSOToken{
+		StartURL:    cached.StartURL,
+		Region:      cached.Region,
+		ExpiresAt:   expiresAt,
+		File:        path,
+		accessToken: cached.AccessToken,
+	}
+}
+
+// SessionForProfile returns the SSO session a profile logs in with, or nil if it doesn't use SSO
+// Legacy profiles with inline sso_start_url get a session logged in with --profile
+func SessionForProfile(profile *Profile, sessions []*SSOSession) *SSOSession {
+	if profile == nil {
+		return nil
+	}
+	if profile.SSOSession != "" {
+		for _, session := range sessions {
+			if session.Name == profile.SSOSession {
+				return session
+			}
+		}
+		return &SSOSession{Name: profile.SSOSession}
+	}
+	if profile.SSOStartURL != "" {
+		return &SSOSession{Name: profile.Name, StartURL: profile.SSOStartURL, Region: profile.SSORegion, Profile: profile.Name}
+	}
+	return nil
+}
+
+// FormatTimeToExpiry describes how long a token stays valid, e.g. "3h12m left" or "expired"
+func FormatTimeToExpiry(token *SSOToken) string {
+	if token == nil {
+		return "not logged in"
+	}
+	remaining := time.Until(token.ExpiresAt)
+	if remaining <= 0 {
+		return "expired"
+	}
+	hours := int(remaining.Hours())
+	minutes := int(remaining.Minutes()) % 60
+	if hours == 0 {
+		return fmt.Sprintf("%dm left", minutes)
+	}
+	return fmt.Sprintf("%dh%02dm left", hours, minutes)
+}
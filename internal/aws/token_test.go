@@ -0,0 +1,152 @@
+package aws
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeSSOCache writes cache files below a fresh home directory
+func writeSSOCache(t *testing.T, files map[string]string) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	cacheDir := filepath.Join(home, ".aws", "sso", "cache")
+	if err := os.MkdirAll(cacheDir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(cacheDir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return cacheDir
+}
+
+// cacheFileName returns the name the AWS CLI gives the token cache file of a session name or start URL
+func cacheFileName(key string) string {
+	sum := sha1.Sum([]byte(key))
+	return hex.EncodeToString(sum[:]) + ".json"
+}
+
+func TestFindSSOToken(t *testing.T) {
+	later := time.Now().Add(2 * time.Hour).UTC().Format(time.RFC3339)
+	latest := time.Now().Add(5 * time.Hour).UTC().Format(time.RFC3339)
+	cacheDir := writeSSOCache(t, map[string]string{
+		cacheFileName("corp"):                 `{"startUrl": "https://corp.awsapps.com/start", "accessToken": "a", "expiresAt": "` + later + `"}`,
+		"renamed.json":                        `{"startUrl": "https://corp.awsapps.com/start", "accessToken": "b", "expiresAt": "` + latest + `"}`,
+		"registration.json":                   `{"clientId": "c", "clientSecret": "d", "expiresAt": "` + latest + `"}`,
+		cacheFileName("https://legacy/start"): `{"startUrl": "https://legacy/start", "accessToken": "e", "expiresAt": "2020-01-02T03:04:05UTC"}`,
+		"broken.json":                         `{`,
+	})
+
+	tests := []struct {
+		name     string
+		session  *SSOSession
+		wantFile string // "" for no token
+	}{
+		{
+			name:     "by start URL, expiring last",
+			session:  &SSOSession{Name: "corp", StartURL: "https://corp.awsapps.com/start"},
+			wantFile: "renamed.json",
+		},
+		{
+			name:     "by session name",
+			session:  &SSOSession{Name: "corp"},
+			wantFile: cacheFileName("corp"),
+		},
+		{
+			name:     "legacy profile by start URL hash",
+			session:  &SSOSession{Name: "legacy", StartURL: "https://legacy/start", Profile: "legacy"},
+			wantFile: cacheFileName("https://legacy/start"),
+		},
+		{name: "not logged in", session: &SSOSession{Name: "other", StartURL: "https://other/start"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := FindSSOToken(tt.session)
+			if err != nil {
+				t.Fatalf("FindSSOToken() error = %v", err)
+			}
+			if tt.wantFile == "" {
+				if token != nil {
+					t.Errorf("FindSSOToken() = %s, want no token", token.File)
+				}
+				return
+			}
+			if token == nil || token.File != filepath.Join(cacheDir, tt.wantFile) {
+				t.Errorf("FindSSOToken() = %+v, want the token of %s", token, tt.wantFile)
+			}
+		})
+	}
+
+	legacy, _ := FindSSOToken(&SSOSession{Name: "legacy", StartURL: "https://legacy/start", Profile: "legacy"})
+	if !legacy.Expired() || !legacy.ExpiresAt.Equal(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("legacy token expires at %v, want the old CLI date format parsed and expired", legacy.ExpiresAt)
+	}
+}
+
+func TestFindSSOTokenWithoutCache(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	token, err := FindSSOToken(&SSOSession{Name: "corp"})
+	if token != nil || err != nil {
+		t.Errorf("FindSSOToken() = %v, %v, want no token and no error", token, err)
+	}
+}
+
+func TestFormatTimeToExpiry(t *testing.T) {
+	tests := []struct {
+		name  string
+		token *SSOToken
+		want  string
+	}{
+		{"no token", nil, "not logged in"},
+		{"expired", &SSOToken{ExpiresAt: time.Now().Add(-time.Minute)}, "expired"},
+		{"minutes", &SSOToken{ExpiresAt: time.Now().Add(42*time.Minute + 30*time.Second)}, "42m left"},
+		{"hours", &SSOToken{ExpiresAt: time.Now().Add(3*time.Hour + 5*time.Minute + 30*time.Second)}, "3h05m left"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatTimeToExpiry(tt.token); got != tt.want {
+				t.Errorf("FormatTimeToExpiry() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSessionForProfile(t *testing.T) {
+	sessions := []*SSOSession{{Name: "corp", StartURL: "https://corp.awsapps.com/start"}}
+
+	tests := []struct {
+		name        string
+		profile     *Profile
+		wantSession string // "" for no session
+		wantProfile string // profile the session logs in with, for legacy profiles
+	}{
+		{name: "no profile"},
+		{name: "not SSO", profile: &Profile{Name: "ci", StaticKeys: true}},
+		{name: "configured session", profile: &Profile{Name: "dev", SSOSession: "corp"}, wantSession: "corp"},
+		{name: "unknown session", profile: &Profile{Name: "dev", SSOSession: "gone"}, wantSession: "gone"},
+		{name: "legacy inline settings", profile: &Profile{Name: "old", SSOStartURL: "https://old/start"}, wantSession: "old", wantProfile: "old"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := SessionForProfile(tt.profile, sessions)
+			if tt.wantSession == "" {
+				if session != nil {
+					t.Errorf("SessionForProfile() = %+v, want none", session)
+				}
+				return
+			}
+			if session == nil || session.Name != tt.wantSession || session.Profile != tt.wantProfile {
+				t.Errorf("SessionForProfile() = %+v, want session %q logged in with profile %q", session, tt.wantSession, tt.wantProfile)
+			}
+		})
+	}
+}
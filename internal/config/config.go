@@ -6,6 +6,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -21,6 +22,19 @@ func ConfigPath() (string, error) {
 	return configPath, nil
 }
 
+// ExpandHome replaces a leading ~ with the user's home directory
+// Example: ~/Projects -> /home/username/Projects
+func ExpandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, "~"+string(filepath.Separator)) {
+		return path, nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, path[1:]), nil
+}
+
 // Load reads and parses the config file
 // Returns DefaultConfig() if file doesn't exist
 func Load() (Config, error) {
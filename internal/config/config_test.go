@@ -0,0 +1,30 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestExpandHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"~", home},
+		{"~/Projects", filepath.Join(home, "Projects")},
+		{"~other/Projects", "~other/Projects"},
+		{"/srv/~/infra", "/srv/~/infra"},
+		{"infra", "infra"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got, err := ExpandHome(tt.path); err != nil || got != tt.want {
+				t.Errorf("ExpandHome(%q) = %q, %v, want %q", tt.path, got, err, tt.want)
+			}
+		})
+	}
+}
@@ -1,8 +1,102 @@
 package config
 
+import (
+	"path"
+	"path/filepath"
+)
+
 type Config struct {
-	SearchPaths    []string `yaml:"search_paths"`
-	IgnorePatterns []string `yaml:"ignore_patterns,omitempty"`
+	SearchPaths          []string                 `yaml:"search_paths"`
+	IgnorePatterns       []string                 `yaml:"ignore_patterns,omitempty"`
+	Engine               string                   `yaml:"engine,omitempty"` // "terraform" or "tofu"
+	TerraformInstallDirs []string                 `yaml:"terraform_install_dirs,omitempty"`
+	TofuInstallDirs      []string                 `yaml:"tofu_install_dirs,omitempty"`
+	StrictVersionCheck   bool                     `yaml:"strict_version_check,omitempty"`
+	VarFileLayers        []string                 `yaml:"var_file_layers,omitempty"` // see ProjectConfig.VarFileLayers
+	VarFileRoots         []string                 `yaml:"var_file_roots,omitempty"`  // see ProjectConfig.VarFileRoots
+	Conventions          NamingConventions        `yaml:"conventions,omitempty"`     // see ProjectConfig.Conventions
+	AWS                  []AWSMapping             `yaml:"aws,omitempty"`             // see ProjectConfig.AWS
+	Google               []GoogleMapping          `yaml:"google,omitempty"`          // see ProjectConfig.Google
+	Azure                []AzureMapping           `yaml:"azure,omitempty"`           // see ProjectConfig.Azure
+	SSOLoginMode         string                   `yaml:"sso_login_mode,omitempty"`  // "browser", "no-browser" or "device-code"
+	Projects             map[string]ProjectConfig `yaml:"projects,omitempty"`        // keyed by project path, absolute or relative to a search path
+}
+
+// ProjectConfig holds settings that override the global config for a single project.
+type ProjectConfig struct {
+	Engine string        `yaml:"engine,omitempty"` // "terraform" or "tofu"
+	Init   *InitDefaults `yaml:"init,omitempty"`   // last init options used, pre-filled in the init form
+	// Ordered var file rules for an environment: project-relative globs where {env} is the
+	// environment name, "{env}" alone being the environment's own file.
+	// e.g. ["common.tfvars", "{env}"] passes -var-file=common.tfvars -var-file=<env file>
+	VarFileLayers []string `yaml:"var_file_layers,omitempty"`
+	// Project-relative directories scanned for var files, "/**" suffix for recursive scans
+	// e.g. [".", "envs/**"] finds terraform.tfvars in envs/dev/ and envs/prod/
+	VarFileRoots []string `yaml:"var_file_roots,omitempty"`
+	// How var and backend files are found and named, fields left empty use the global conventions
+	Conventions NamingConventions `yaml:"conventions,omitempty"`
+	// AWS profile and region per environment, checked before the global mappings
+	AWS []AWSMapping `yaml:"aws,omitempty"`
+	// Google Cloud project and account per environment, checked before the global mappings
+	Google []GoogleMapping `yaml:"google,omitempty"`
+	// Azure subscription and tenant per environment, checked before the global mappings
+	Azure []AzureMapping `yaml:"azure,omitempty"`
+}
+
+// AWSMapping selects the AWS profile and region commands run with for matching environments.
+type AWSMapping struct {
+	Env     string `yaml:"env"`               // env name or pattern, e.g. "prod", "*-prod", "eu-west-1/*"
+	Profile string `yaml:"profile,omitempty"` // passed as AWS_PROFILE
+	Region  string `yaml:"region,omitempty"`  // passed as AWS_REGION and AWS_DEFAULT_REGION
+	Account string `yaml:"account,omitempty"` // expected AWS account ID, apply is blocked in any other
+}
+
+// GoogleMapping selects the Google Cloud project and account commands run with for matching environments.
+type GoogleMapping struct {
+	Env     string `yaml:"env"`               // env name or pattern, as in AWSMapping
+	Project string `yaml:"project,omitempty"` // passed as GOOGLE_PROJECT and CLOUDSDK_CORE_PROJECT
+	Account string `yaml:"account,omitempty"` // gcloud account, passed as CLOUDSDK_CORE_ACCOUNT
+}
+
+// AzureMapping selects the Azure subscription and tenant commands run with for matching environments.
+type AzureMapping struct {
+	Env          string `yaml:"env"`                    // env name or pattern, as in AWSMapping
+	Subscription string `yaml:"subscription,omitempty"` // subscription ID, passed as ARM_SUBSCRIPTION_ID
+	Tenant       string `yaml:"tenant,omitempty"`       // tenant ID, passed as ARM_TENANT_ID and to az login
+}
+
+// NamingConventions describe a project layout's var and backend files.
+// Globs are project-relative and support ** (e.g. "variables/backend/**/*.tfvars").
+// Env patterns are either a template with an {env} placeholder ("backend_{env}.tfvars")
+// or a regular expression with an env group ("^backend-(?P<env>.+)\.tfvars$").
+// Patterns containing a "/" are matched against the project-relative path, others
+// against the file name.
+type NamingConventions struct {
+	VarFileGlob     string `yaml:"var_file_glob,omitempty"`     // replaces var_file_roots when set
+	VarFileEnv      string `yaml:"var_file_env,omitempty"`      // env name of a var file
+	BackendFileGlob string `yaml:"backend_file_glob,omitempty"` // where backend config files live
+	BackendFileEnv  string `yaml:"backend_file_env,omitempty"`  // env name of a backend file
+}
+
+// InitDefaults are the init options remembered for a project.
+type InitDefaults struct {
+	Reconfigure  bool   `yaml:"reconfigure"`
+	MigrateState bool   `yaml:"migrate_state"`
+	ForceCopy    bool   `yaml:"force_copy"`
+	Upgrade      bool   `yaml:"upgrade"`
+	NoBackend    bool   `yaml:"no_backend"`
+	PluginDir    string `yaml:"plugin_dir,omitempty"`
+	LockfileMode string `yaml:"lockfile_mode,omitempty"`
+	// -backend-config key=value pairs per environment name, pairs holding secrets aren't saved
+	BackendConfig map[string][]string `yaml:"env_backend_config,omitempty"`
+}
+
+// DefaultInitDefaults returns the init options used when a project has none remembered.
+func DefaultInitDefaults() InitDefaults {
+	return InitDefaults{
+		Reconfigure: true,
+		Upgrade:     true,
+	}
 }
 
 func DefaultConfig() Config {
@@ -18,5 +112,197 @@ func DefaultConfig() Config {
 			"*/vendor",
 			"*/.terraform",
 		},
+		TerraformInstallDirs: []string{
+			"~/.tfenv/versions",
+			"~/.asdf/installs/terraform",
+		},
+		TofuInstallDirs: []string{
+			"~/.tofuenv/versions",
+			"~/.asdf/installs/opentofu",
+		},
+	}
+}
+
+// InstallDirs returns the directories searched for versioned binaries of the given engine.
+// Falls back to the default tfenv/tofuenv/asdf locations when none are configured.
+func (c Config) InstallDirs(engine string) []string {
+	if engine == "tofu" {
+		if len(c.TofuInstallDirs) > 0 {
+			return c.TofuInstallDirs
+		}
+		return DefaultConfig().TofuInstallDirs
+	}
+	if len(c.TerraformInstallDirs) > 0 {
+		return c.TerraformInstallDirs
+	}
+	return DefaultConfig().TerraformInstallDirs
+}
+
+// DefaultVarFileLayers returns the layering rules used when none are configured:
+// a shared common.tfvars (at the root or in variables/) followed by the environment's file.
+func DefaultVarFileLayers() []string {
+	return []string{"common.tfvars", "variables/common.tfvars", "{env}"}
+}
+
+// VarFileLayersFor returns the var file layering rules for a project:
+// the project's own rules, then the global rules, then the defaults.
+func (c Config) VarFileLayersFor(projectPath string) []string {
+	if layers := c.ProjectSettings(projectPath).VarFileLayers; len(layers) > 0 {
+		return layers
+	}
+	if len(c.VarFileLayers) > 0 {
+		return c.VarFileLayers
+	}
+	return DefaultVarFileLayers()
+}
+
+// DefaultVarFileRoots returns the directories scanned for var files when none are configured.
+func DefaultVarFileRoots() []string {
+	return []string{".", "variables/**", "env/**", "envs/**", "tfvars/**"}
+}
+
+// VarFileRootsFor returns the var file roots for a project:
+// the project's own roots, then the global roots, then the defaults.
+func (c Config) VarFileRootsFor(projectPath string) []string {
+	if roots := c.ProjectSettings(projectPath).VarFileRoots; len(roots) > 0 {
+		return roots
+	}
+	if len(c.VarFileRoots) > 0 {
+		return c.VarFileRoots
+	}
+	return DefaultVarFileRoots()
+}
+
+// DefaultConventions returns the conventions used for fields that aren't configured:
+// backend files in variables/backend/ named backend_<env>.tfvars.
+func DefaultConventions() NamingConventions {
+	return NamingConventions{
+		BackendFileGlob: "variables/backend/**/*.tfvars",
+		BackendFileEnv:  "backend_{env}.tfvars",
+	}
+}
+
+// ConventionsFor returns the naming conventions of a project, field by field:
+// the project's own setting, then the global setting, then the default.
+func (c Config) ConventionsFor(projectPath string) NamingConventions {
+	project := c.ProjectSettings(projectPath).Conventions
+	defaults := DefaultConventions()
+	pick := func(values ...string) string {
+		for _, value := range values {
+			if value != "" {
+				return value
+			}
+		}
+		return ""
+	}
+	return NamingConventions{
+		VarFileGlob:     pick(project.VarFileGlob, c.Conventions.VarFileGlob, defaults.VarFileGlob),
+		VarFileEnv:      pick(project.VarFileEnv, c.Conventions.VarFileEnv, defaults.VarFileEnv),
+		BackendFileGlob: pick(project.BackendFileGlob, c.Conventions.BackendFileGlob, defaults.BackendFileGlob),
+		BackendFileEnv:  pick(project.BackendFileEnv, c.Conventions.BackendFileEnv, defaults.BackendFileEnv),
+	}
+}
+
+// AWSMappingFor returns the first AWS mapping matching an environment of a project,
+// project mappings first, then global ones.
+func (c Config) AWSMappingFor(projectPath, envName string) (AWSMapping, bool) {
+	if envName == "" {
+		return AWSMapping{}, false
+	}
+	mappings := append(append([]AWSMapping{}, c.ProjectSettings(projectPath).AWS...), c.AWS...)
+	for _, mapping := range mappings {
+		if envMatches(mapping.Env, envName) {
+			return mapping, true
+		}
+	}
+	return AWSMapping{}, false
+}
+
+// GoogleMappingFor returns the first Google Cloud mapping matching an environment of a project,
+// project mappings first, then global ones.
+func (c Config) GoogleMappingFor(projectPath, envName string) (GoogleMapping, bool) {
+	if envName == "" {
+		return GoogleMapping{}, false
+	}
+	mappings := append(append([]GoogleMapping{}, c.ProjectSettings(projectPath).Google...), c.Google...)
+	for _, mapping := range mappings {
+		if envMatches(mapping.Env, envName) {
+			return mapping, true
+		}
+	}
+	return GoogleMapping{}, false
+}
+
+// AzureMappingFor returns the first Azure mapping matching an environment of a project,
+// project mappings first, then global ones.
+func (c Config) AzureMappingFor(projectPath, envName string) (AzureMapping, bool) {
+	if envName == "" {
+		return AzureMapping{}, false
+	}
+	mappings := append(append([]AzureMapping{}, c.ProjectSettings(projectPath).Azure...), c.Azure...)
+	for _, mapping := range mappings {
+		if envMatches(mapping.Env, envName) {
+			return mapping, true
+		}
+	}
+	return AzureMapping{}, false
+}
+
+// envMatches reports whether an environment name matches a mapping's env name or pattern.
+func envMatches(pattern, envName string) bool {
+	if pattern == envName {
+		return true
+	}
+	matched, err := path.Match(pattern, envName)
+	return err == nil && matched
+}
+
+// ProjectSettings returns the per-project overrides for the project at projectPath.
+// Keys in the projects map are a full path (~ allowed) or a path relative to a search path.
+func (c Config) ProjectSettings(projectPath string) ProjectConfig {
+	if key, ok := c.projectKey(projectPath); ok {
+		return c.Projects[key]
+	}
+	return ProjectConfig{}
+}
+
+// SetProjectSettings stores the overrides for the project at projectPath,
+// reusing the existing key for that project if there is one.
+func (c *Config) SetProjectSettings(projectPath string, settings ProjectConfig) {
+	if c.Projects == nil {
+		c.Projects = make(map[string]ProjectConfig)
+	}
+	key, ok := c.projectKey(projectPath)
+	if !ok {
+		key, _ = filepath.Abs(projectPath)
+	}
+	c.Projects[key] = settings
+}
+
+// projectKey finds the projects map key matching projectPath.
+// Relative keys are resolved against each search path, so projects with the same
+// directory name in different places don't share their settings.
+func (c Config) projectKey(projectPath string) (string, bool) {
+	absPath, _ := filepath.Abs(projectPath)
+
+	for key := range c.Projects {
+		path, err := ExpandHome(key)
+		if err != nil {
+			continue
+		}
+		candidates := []string{path}
+		if !filepath.IsAbs(path) {
+			for _, searchPath := range c.SearchPaths {
+				if searchPath, err := ExpandHome(searchPath); err == nil {
+					candidates = append(candidates, filepath.Join(searchPath, path))
+				}
+			}
+		}
+		for _, candidate := range candidates {
+			if absKey, err := filepath.Abs(candidate); err == nil && absKey == absPath {
+				return key, true
+			}
+		}
 	}
+	return "", false
 }
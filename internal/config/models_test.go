@@ -0,0 +1,96 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestProjectSettings(t *testing.T) {
+	root := t.TempDir()
+	cfg := Config{
+		SearchPaths: []string{root},
+		Projects: map[string]ProjectConfig{
+			filepath.Join(root, "team-a", "network"): {Engine: "tofu"},
+			"team-b/network":                         {Engine: "terraform"},
+			"app":                                    {VarFileRoots: []string{"envs/**"}},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		projectPath string
+		wantEngine  string
+		wantRoots   int
+	}{
+		{name: "absolute key", projectPath: filepath.Join(root, "team-a", "network"), wantEngine: "tofu"},
+		{name: "key relative to a search path", projectPath: filepath.Join(root, "team-b", "network"), wantEngine: "terraform"},
+		{name: "project directly in a search path", projectPath: filepath.Join(root, "app"), wantRoots: 1},
+		{name: "same directory name elsewhere", projectPath: filepath.Join(root, "team-c", "network")},
+		{name: "same directory name deeper", projectPath: filepath.Join(root, "team-c", "app")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := cfg.ProjectSettings(tt.projectPath)
+			if settings.Engine != tt.wantEngine || len(settings.VarFileRoots) != tt.wantRoots {
+				t.Errorf("ProjectSettings() = %+v, want engine %q and %d var file roots", settings, tt.wantEngine, tt.wantRoots)
+			}
+		})
+	}
+}
+
+func TestSetProjectSettings(t *testing.T) {
+	root := t.TempDir()
+	cfg := Config{SearchPaths: []string{root}, Projects: map[string]ProjectConfig{"network": {Engine: "tofu"}}}
+
+	// An existing key is reused, new projects are keyed by absolute path
+	cfg.SetProjectSettings(filepath.Join(root, "network"), ProjectConfig{Engine: "terraform"})
+	cfg.SetProjectSettings(filepath.Join(root, "sub", "network"), ProjectConfig{Engine: "tofu"})
+
+	if len(cfg.Projects) != 2 || cfg.Projects["network"].Engine != "terraform" {
+		t.Errorf("SetProjectSettings() gave %+v, want the network key updated", cfg.Projects)
+	}
+	if got := cfg.Projects[filepath.Join(root, "sub", "network")].Engine; got != "tofu" {
+		t.Errorf("SetProjectSettings() stored engine %q under the absolute path, want tofu", got)
+	}
+}
+
+func TestAWSMappingFor(t *testing.T) {
+	root := t.TempDir()
+	cfg := Config{
+		AWS: []AWSMapping{
+			{Env: "prod", Profile: "global-prod"},
+			{Env: "*-prod", Profile: "regional-prod"},
+			{Env: "eu-west-1/*", Profile: "eu"},
+			{Env: "[", Profile: "bad-pattern"},
+		},
+		Projects: map[string]ProjectConfig{
+			filepath.Join(root, "app"): {AWS: []AWSMapping{{Env: "prod", Profile: "app-prod"}}},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		projectPath string
+		envName     string
+		wantProfile string
+	}{
+		{name: "exact name", projectPath: filepath.Join(root, "other"), envName: "prod", wantProfile: "global-prod"},
+		{name: "project mapping first", projectPath: filepath.Join(root, "app"), envName: "prod", wantProfile: "app-prod"},
+		{name: "global mapping for a project", projectPath: filepath.Join(root, "app"), envName: "eu-prod", wantProfile: "regional-prod"},
+		{name: "pattern with a directory", projectPath: root, envName: "eu-west-1/dev", wantProfile: "eu"},
+		{name: "pattern doesn't cross directories", projectPath: root, envName: "eu-west-1/dev/blue"},
+		{name: "no match", projectPath: root, envName: "dev"},
+		{name: "malformed pattern matches itself only", projectPath: root, envName: "[", wantProfile: "bad-pattern"},
+		{name: "no environment", projectPath: root, envName: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapping, ok := cfg.AWSMappingFor(tt.projectPath, tt.envName)
+			if mapping.Profile != tt.wantProfile || ok != (tt.wantProfile != "") {
+				t.Errorf("AWSMappingFor(%q) = %q, %v, want %q", tt.envName, mapping.Profile, ok, tt.wantProfile)
+			}
+		})
+	}
+}
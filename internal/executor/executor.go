@@ -2,6 +2,7 @@ package executor
 
 import (
 	"bufio"
+	"os"
 	"os/exec"
 	"strings"
 	"sync"
@@ -14,11 +15,20 @@ import (
 // args: command arguments
 // workingDir: directory to run the command in (empty string for current dir)
 func ExecuteStreaming(commandName string, args []string, workingDir string) tea.Cmd {
+	return ExecuteStreamingWithEnv(commandName, args, workingDir, nil)
+}
+
+// ExecuteStreamingWithEnv is like ExecuteStreaming but adds extra environment variables
+// env: "KEY=VALUE" pairs appended to the current process environment (later entries win)
+func ExecuteStreamingWithEnv(commandName string, args []string, workingDir string, env []string) tea.Cmd {
 	// Create the command
 	cmd := exec.Command(commandName, args...)
 	if workingDir != "" {
 		cmd.Dir = workingDir
 	}
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
 	cmdString := commandName + " " + strings.Join(args, " ")
 
 	// Get stdout and stderr pipes
@@ -89,31 +99,45 @@ func ExecuteStreaming(commandName string, args []string, workingDir string) tea.
 	}()
 
 	// Launch a goroutine to wait for command completion
+	exitCode := new(int)
 	go func() {
-		cmd.Wait()           // Wait for command to finish
-		wg.Wait()            // Wait for stdout and stderr goroutines to finish reading
+		wg.Wait() // Wait for stdout and stderr goroutines to finish reading (Wait closes the pipes)
+		*exitCode = exitCodeOf(cmd.Wait())
 		close(outputChannel) // NOW it's safe to close the channel
 	}()
 
 	// Return a listener that will read from the channel
-	return listenToChannel(outputChannel, cmdString)
+	return listenToChannel(outputChannel, cmdString, exitCode)
+}
+
+// exitCodeOf converts the error returned by cmd.Wait into an exit code
+// (-1 when the command didn't exit normally, e.g. killed by a signal)
+func exitCodeOf(err error) int {
+	if err == nil {
+		return 0
+	}
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return exitErr.ExitCode()
+	}
+	return -1
 }
 
 // listenToChannel creates a tea.Cmd that reads one message from a channel
 // This function will be called repeatedly by the UI's Update function
-func listenToChannel(ch chan CommandOutputMsg, cmdString string) tea.Cmd {
+// exitCode is set before the channel is closed
+func listenToChannel(ch chan CommandOutputMsg, cmdString string, exitCode *int) tea.Cmd {
 	return func() tea.Msg {
 		msg, ok := <-ch
 		if !ok {
 			// Channel closed, command completed
 			return CommandCompletedMsg{
 				Command:  cmdString,
-				ExitCode: 0,
+				ExitCode: *exitCode,
 				Output:   "",
 			}
 		}
 		// Include the command to listen for the next message
-		msg.ListenNext = listenToChannel(ch, cmdString)
+		msg.ListenNext = listenToChannel(ch, cmdString, exitCode)
 		return msg
 	}
 }
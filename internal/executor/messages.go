@@ -9,7 +9,7 @@ type CommandOutputMsg struct {
 	ListenNext tea.Cmd // Command to listen for next message
 }
 
-// CommandCompletedMsg is sent when a command finishes successfully
+// CommandCompletedMsg is sent when a command finishes, ExitCode is non-zero when it failed
 type CommandCompletedMsg struct {
 	Command  string
 	ExitCode int
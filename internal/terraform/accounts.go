@@ -0,0 +1,84 @@
+// Package terraform provides functionality for detecting, discovering,
+// and interacting with Terraform projects and resources.
+//
+// This file contains AWS account guard logic:
+// - ParseAWSAccountConstraints: Read allowed_account_ids/forbidden_account_ids from the aws provider
+// - Check: Compare the account commands would run in with the expected ones
+package terraform
+
+import (
+	"regexp"
+	"strings"
+)
+
+var accountIDPattern = regexp.MustCompile(`^\d{12}$`)
+
+// AccountConstraints describe which AWS accounts a project's environment may run in.
+type AccountConstraints struct {
+	Expected  string   // account configured for the environment, "" if none
+	Allowed   []string // allowed_account_ids of the default aws provider
+	Forbidden []string // forbidden_account_ids of the default aws provider
+}
+
+// ParseAWSAccountConstraints reads the account lists of the project's default
+// (unaliased) aws provider blocks. Aliased providers usually assume roles in
+// other accounts, so their lists don't say anything about the caller's account.
+func ParseAWSAccountConstraints(projectPath string) AccountConstraints {
+	var constraints AccountConstraints
+
+	files := parseProjectSources(projectPath)
+	for _, block := range topLevelBlocks(files, "provider") {
+		if len(block.Labels) != 1 || block.Labels[0] != "aws" {
+			continue
+		}
+		if _, aliased := block.Body.Attributes["alias"]; aliased {
+			continue
+		}
+		file := sourceFile(files, block)
+		constraints.Allowed = append(constraints.Allowed, accountIDs(stringListAttribute(file, block.Body, "allowed_account_ids"))...)
+		constraints.Forbidden = append(constraints.Forbidden, accountIDs(stringListAttribute(file, block.Body, "forbidden_account_ids"))...)
+	}
+
+	return constraints
+}
+
+// accountIDs keeps the literal 12-digit account IDs of a list, dropping
+// expressions (e.g. var.account_ids) that can't be evaluated here.
+func accountIDs(items []string) []string {
+	var ids []string
+	for _, item := range items {
+		if accountIDPattern.MatchString(item) {
+			ids = append(ids, item)
+		}
+	}
+	return ids
+}
+
+// IsEmpty reports whether there is nothing to check the account against.
+func (c AccountConstraints) IsEmpty() bool {
+	return c.Expected == "" && len(c.Allowed) == 0 && len(c.Forbidden) == 0
+}
+
+// Check returns why the given account may not be used, or "" if it may.
+func (c AccountConstraints) Check(account string) string {
+	if c.Expected != "" && account != c.Expected {
+		return "account " + account + " is not the environment's account " + c.Expected
+	}
+	if len(c.Allowed) > 0 && !containsString(c.Allowed, account) {
+		return "account " + account + " is not in allowed_account_ids (" + strings.Join(c.Allowed, ", ") + ")"
+	}
+	if containsString(c.Forbidden, account) {
+		return "account " + account + " is in forbidden_account_ids"
+	}
+	return ""
+}
+
+// containsString reports whether list contains value.
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
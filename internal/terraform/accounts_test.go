@@ -0,0 +1,94 @@
+package terraform
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseAWSAccountConstraints(t *testing.T) {
+	projectPath := t.TempDir()
+	writeFile(t, projectPath, "providers.tf", `
+provider "aws" {
+  region              = "eu-west-1"
+  allowed_account_ids = ["123456789012", var.extra_account, "not-an-id"]
+}
+
+provider "aws" {
+  alias               = "shared"
+  allowed_account_ids = ["999999999999"]
+}
+
+provider "google" {
+  project = "p"
+}
+`, 0o644)
+	writeFile(t, projectPath, "more.tf", `
+provider "aws" {
+  region                = "us-east-1"
+  forbidden_account_ids = ["210987654321"]
+}
+`, 0o644)
+
+	constraints := ParseAWSAccountConstraints(projectPath)
+	if want := []string{"123456789012"}; !reflect.DeepEqual(constraints.Allowed, want) {
+		t.Errorf("Allowed = %v, want %v", constraints.Allowed, want)
+	}
+	if want := []string{"210987654321"}; !reflect.DeepEqual(constraints.Forbidden, want) {
+		t.Errorf("Forbidden = %v, want %v", constraints.Forbidden, want)
+	}
+
+	if empty := ParseAWSAccountConstraints(t.TempDir()); !empty.IsEmpty() {
+		t.Errorf("ParseAWSAccountConstraints() of an empty project = %+v, want no constraints", empty)
+	}
+}
+
+func TestAccountConstraintsCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		constraints AccountConstraints
+		account     string
+		wantReason  string // substring, "" when the account may be used
+	}{
+		{name: "no constraints", account: "123456789012"},
+		{name: "expected account", constraints: AccountConstraints{Expected: "123456789012"}, account: "123456789012"},
+		{
+			name:        "other than expected",
+			constraints: AccountConstraints{Expected: "123456789012"},
+			account:     "210987654321",
+			wantReason:  "not the environment's account 123456789012",
+		},
+		{
+			name:        "allowed",
+			constraints: AccountConstraints{Allowed: []string{"111111111111", "123456789012"}},
+			account:     "123456789012",
+		},
+		{
+			name:        "not allowed",
+			constraints: AccountConstraints{Allowed: []string{"111111111111", "123456789012"}},
+			account:     "210987654321",
+			wantReason:  "not in allowed_account_ids (111111111111, 123456789012)",
+		},
+		{
+			name:        "forbidden",
+			constraints: AccountConstraints{Forbidden: []string{"210987654321"}},
+			account:     "210987654321",
+			wantReason:  "is in forbidden_account_ids",
+		},
+		{
+			name:        "expected but forbidden",
+			constraints: AccountConstraints{Expected: "210987654321", Forbidden: []string{"210987654321"}},
+			account:     "210987654321",
+			wantReason:  "is in forbidden_account_ids",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason := tt.constraints.Check(tt.account)
+			if (reason == "") != (tt.wantReason == "") || !strings.Contains(reason, tt.wantReason) {
+				t.Errorf("Check(%q) = %q, want %q", tt.account, reason, tt.wantReason)
+			}
+		})
+	}
+}
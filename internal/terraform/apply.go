@@ -0,0 +1,22 @@
+package terraform
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type ApplyOptions struct {
+	// Saved plan to apply (see SavedPlanFile), the user confirmed it after ShowPlan.
+	// Terraform doesn't prompt for a saved plan, and its var files were set at plan time.
+	PlanFile string
+	Input    bool
+}
+
+func RunApply(ctx CommandContext, options ApplyOptions) tea.Cmd {
+	args := []string{"apply"}
+
+	if !options.Input {
+		args = append(args, "-input=false")
+	}
+	args = append(args, options.PlanFile)
+	return ctx.run(args)
+}
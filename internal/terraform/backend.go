@@ -2,25 +2,33 @@
 // and interacting with Terraform projects and resources.
 //
 // This file contains backend var file discovery and matching logic:
-// - DiscoverBackendVarFiles: Find backend .tfvars files
-// - extractEnvFromBackendFile: Extract environment name from filename
+// - DiscoverBackendVarFiles: Find backend files following the project's conventions
+// - extractEnvFromBackendFile: Extract environment name from filename (fallback)
 // - MatchBackendsForEnv: Match backend configs to environment names
+// - BackendLocation: Describe where a backend config stores the state
+// - FormatBackendSettings: Format a backend file's parsed contents for UI display
 // - FormatBackendInfo: Format backend info for UI display
 package terraform
 
 import (
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/Nicolas-Rigaudy/lazytf/internal/config"
 )
 
 // DiscoverBackendVarFiles scans for backend configuration files in the project.
-// It looks for .tfvars files in variables/backend/** subdirectories.
-func DiscoverBackendVarFiles(projectPath string) ([]BackendVarFile, error) {
+// Files are found and named according to the project's conventions,
+// by default .tfvars files in variables/backend/** named backend_<env>.tfvars.
+func DiscoverBackendVarFiles(projectPath string, conventions config.NamingConventions) ([]BackendVarFile, error) {
 	var backendFiles []BackendVarFile
 
-	// Look for backend configs in variables/backend/**/*.tfvars
-	backendDir := filepath.Join(projectPath, "variables", "backend")
+	// Only the static part of the glob needs to be walked
+	backendDir := filepath.Join(projectPath, globRoot(conventions.BackendFileGlob))
 
 	// Check if backend directory exists
 	if _, err := os.Stat(backendDir); os.IsNotExist(err) {
@@ -36,32 +44,41 @@ func DiscoverBackendVarFiles(projectPath string) ([]BackendVarFile, error) {
 
 		// Skip directories
 		if info.IsDir() {
+			if path != backendDir && strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
 			return nil
 		}
 
-		// Only process .tfvars files
-		if !strings.HasSuffix(info.Name(), ".tfvars") {
+		// Get relative path from project root
+		relFile, err := filepath.Rel(projectPath, path)
+		if err != nil {
 			return nil
 		}
 
-		// Get relative path from project root
-		relPath, err := filepath.Rel(projectPath, filepath.Dir(path))
-		if err != nil {
-			relPath = filepath.Dir(path)
+		// Only process files matching the backend glob
+		if !MatchGlob(conventions.BackendFileGlob, relFile) {
+			return nil
 		}
 
-		// Extract environment name from filename
+		// Extract environment name from the path
 		// e.g., "backend_dev2.tfvars" -> "dev2"
 		// or "backend.tfvars" -> "" (generic backend)
-		envName := extractEnvFromBackendFile(info.Name())
+		envName, ok := ExtractEnv(conventions.BackendFileEnv, relFile)
+		if !ok {
+			envName = extractEnvFromBackendFile(info.Name())
+		}
 
 		backendFile := BackendVarFile{
 			Name:     info.Name(),
-			Path:     relPath,
+			Path:     filepath.Dir(relFile),
 			FullPath: path,
 			EnvName:  envName,
 		}
 
+		// Backend files use the var file syntax (bucket = "...", key = "...")
+		backendFile.Settings, backendFile.ParseErr = ParseVarFile(path)
+
 		backendFiles = append(backendFiles, backendFile)
 		return nil
 	})
@@ -73,14 +90,15 @@ func DiscoverBackendVarFiles(projectPath string) ([]BackendVarFile, error) {
 	return backendFiles, nil
 }
 
-// extractEnvFromBackendFile extracts the environment name from a backend filename.
+// extractEnvFromBackendFile extracts the environment name from a backend filename
+// that doesn't match the project's env pattern.
 // Examples:
 //   - "backend_dev2.tfvars" -> "dev2"
-//   - "backend_int.tfvars" -> "int"
 //   - "backend.tfvars" -> "" (generic, applies to all)
+//   - "staging.tfvars" -> "staging"
 func extractEnvFromBackendFile(filename string) string {
-	// Remove .tfvars extension
-	name := strings.TrimSuffix(filename, ".tfvars")
+	// Remove .tfvars/.tfvars.json extension
+	name := trimVarFileExt(strings.TrimSuffix(filename, ".hcl"))
 
 	// Remove "backend_" prefix if present
 	if strings.HasPrefix(name, "backend_") {
@@ -99,9 +117,11 @@ func extractEnvFromBackendFile(filename string) string {
 // MatchBackendsForEnv finds backend config files that match the given environment name.
 // Matching logic:
 //   - Find backend files with matching EnvName
+//   - For disambiguated env names ("eu-west-1/prod"), find backend files named after
+//     the last segment ("prod") if none match the full name
 //   - If no match found, return generic backend.tfvars if it exists
 func MatchBackendsForEnv(envName string, backends []BackendVarFile) []BackendVarFile {
-	var matches []BackendVarFile
+	var matches, segmentMatches []BackendVarFile
 	var genericBackend *BackendVarFile
 
 	for i := range backends {
@@ -116,6 +136,8 @@ func MatchBackendsForEnv(envName string, backends []BackendVarFile) []BackendVar
 		// Check if env name matches
 		if backend.EnvName == envName {
 			matches = append(matches, *backend)
+		} else if strings.HasSuffix(envName, "/"+backend.EnvName) {
+			segmentMatches = append(segmentMatches, *backend)
 		}
 	}
 
@@ -123,6 +145,9 @@ func MatchBackendsForEnv(envName string, backends []BackendVarFile) []BackendVar
 	if len(matches) > 0 {
 		return matches
 	}
+	if len(segmentMatches) > 0 {
+		return segmentMatches
+	}
 
 	// If no matches, return generic backend if it exists
 	if genericBackend != nil {
@@ -133,6 +158,93 @@ func MatchBackendsForEnv(envName string, backends []BackendVarFile) []BackendVar
 	return []BackendVarFile{}
 }
 
+// StringSettings returns the backend file's settings as strings, skipping
+// values that aren't strings, numbers or bools.
+func (b BackendVarFile) StringSettings() map[string]string {
+	settings := make(map[string]string, len(b.Settings))
+	for name, value := range b.Settings {
+		if !value.IsKnown() || value.IsNull() {
+			continue
+		}
+		switch value.Type() {
+		case cty.String:
+			settings[name] = value.AsString()
+		case cty.Number, cty.Bool:
+			settings[name] = FormatValue(value, 0)
+		}
+	}
+	return settings
+}
+
+// BackendLocation describes where a backend stores the state, e.g. "s3://bucket/dev/terraform.tfstate (eu-west-1)".
+// When backendType is "" it is guessed from the settings. Returns "" when unknown.
+func BackendLocation(backendType string, settings map[string]string) string {
+	if backendType == "" {
+		switch {
+		case settings["storage_account_name"] != "":
+			backendType = "azurerm"
+		case settings["bucket"] != "" && settings["key"] != "":
+			backendType = "s3"
+		case settings["bucket"] != "":
+			backendType = "gcs"
+		case settings["path"] != "":
+			backendType = "local"
+		}
+	}
+
+	location := ""
+	switch backendType {
+	case "s3":
+		if settings["bucket"] == "" {
+			return ""
+		}
+		location = "s3://" + settings["bucket"] + "/" + settings["key"]
+		if region := settings["region"]; region != "" {
+			location += " (" + region + ")"
+		}
+		if prefix := settings["workspace_key_prefix"]; prefix != "" {
+			location += ", workspaces under " + prefix + "/"
+		}
+	case "gcs":
+		if settings["bucket"] == "" {
+			return ""
+		}
+		location = "gs://" + strings.TrimSuffix(path.Join(settings["bucket"], settings["prefix"]), "/") + "/<workspace>.tfstate"
+	case "azurerm":
+		if settings["storage_account_name"] == "" {
+			return ""
+		}
+		location = "azurerm://" + settings["storage_account_name"] + "/" + settings["container_name"] + "/" + settings["key"]
+	case "local":
+		location = settings["path"]
+	case "consul":
+		location = "consul://" + settings["address"] + "/" + settings["path"]
+	case "http":
+		location = settings["address"]
+	}
+	return location
+}
+
+// FormatBackendSettings creates a human-readable description of a backend file's
+// contents and the state location they point to.
+func FormatBackendSettings(b BackendVarFile) string {
+	if b.ParseErr != nil {
+		return "⚠️  Could not parse " + b.Name + ": " + b.ParseErr.Error()
+	}
+
+	result := ""
+	if location := BackendLocation("", b.StringSettings()); location != "" {
+		result += "State: " + location + "\n"
+	}
+	for _, name := range sortedKeys(b.Settings) {
+		result += "  " + name + " = " + FormatValue(b.Settings[name], 1) + "\n"
+	}
+	if result == "" {
+		return "(empty backend file)"
+	}
+	return strings.TrimSuffix(result, "\n")
+}
+
 // FormatBackendInfo creates a human-readable string describing backend configuration(s).
 // Handles cases: no backends, single backend, multiple backends.
 func FormatBackendInfo(backends []BackendVarFile) string {
@@ -143,13 +255,21 @@ func FormatBackendInfo(backends []BackendVarFile) string {
 	if len(backends) == 1 {
 		b := backends[0]
 		// Show name and location (e.g., "backend_dev2.tfvars (variables/backend/local)")
-		return b.Name + " (" + b.Path + ")"
+		return b.Name + " (" + b.Path + ")" + formatStateTarget(b)
 	}
 
 	// Multiple backends found
 	result := "Multiple backend options available:\n"
 	for _, b := range backends {
-		result += "  • " + b.Name + " (" + b.Path + ")\n"
+		result += "  • " + b.Name + " (" + b.Path + ")" + formatStateTarget(b) + "\n"
 	}
 	return result
 }
+
+// formatStateTarget returns " → <state location>" for a backend file, or "" when unknown.
+func formatStateTarget(b BackendVarFile) string {
+	if location := BackendLocation("", b.StringSettings()); location != "" {
+		return " → " + location
+	}
+	return ""
+}
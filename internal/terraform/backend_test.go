@@ -0,0 +1,143 @@
+package terraform
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Nicolas-Rigaudy/lazytf/internal/config"
+)
+
+func TestExtractEnvFromBackendFile(t *testing.T) {
+	tests := []struct {
+		filename string
+		want     string
+	}{
+		{"backend_dev2.tfvars", "dev2"},
+		{"backend_prod.tfvars.json", "prod"},
+		{"backend_int.hcl", "int"},
+		{"backend.tfvars", ""},
+		{"backend.hcl", ""},
+		{"staging.tfvars", "staging"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			if got := extractEnvFromBackendFile(tt.filename); got != tt.want {
+				t.Errorf("extractEnvFromBackendFile(%q) = %q, want %q", tt.filename, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiscoverBackendVarFiles(t *testing.T) {
+	projectPath := t.TempDir()
+	writeFile(t, projectPath, "variables/backend/aws/backend_dev.tfvars", "bucket = \"states\"\nkey = \"dev/terraform.tfstate\"\nencrypt = true\n", 0o644)
+	writeFile(t, projectPath, "variables/backend/backend.tfvars", `region = "eu-west-1"`, 0o644)
+	writeFile(t, projectPath, "variables/backend/backend_broken.tfvars", `bucket = `, 0o644)
+	writeFile(t, projectPath, "variables/backend/README.md", "", 0o644)
+	writeFile(t, projectPath, "variables/backend/.old/backend_prod.tfvars", "", 0o644)
+	writeFile(t, projectPath, "variables/dev.tfvars", "", 0o644)
+
+	backends, err := DiscoverBackendVarFiles(projectPath, config.DefaultConventions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]BackendVarFile)
+	for _, backend := range backends {
+		got[backend.Name] = backend
+	}
+	if len(got) != 3 {
+		t.Fatalf("DiscoverBackendVarFiles() found %d files, want 3", len(backends))
+	}
+
+	dev := got["backend_dev.tfvars"]
+	if dev.EnvName != "dev" || dev.Path != filepath.Join("variables", "backend", "aws") {
+		t.Errorf("backend_dev.tfvars has env %q and path %q", dev.EnvName, dev.Path)
+	}
+	wantSettings := map[string]string{"bucket": "states", "key": "dev/terraform.tfstate", "encrypt": "true"}
+	if settings := dev.StringSettings(); !reflect.DeepEqual(settings, wantSettings) {
+		t.Errorf("StringSettings() = %v, want %v", settings, wantSettings)
+	}
+	if generic := got["backend.tfvars"]; generic.EnvName != "" || generic.ParseErr != nil {
+		t.Errorf("backend.tfvars has env %q and parse error %v, want a generic parsed file", generic.EnvName, generic.ParseErr)
+	}
+	if broken := got["backend_broken.tfvars"]; broken.ParseErr == nil || !strings.HasPrefix(FormatBackendSettings(broken), "⚠️  Could not parse") {
+		t.Errorf("backend_broken.tfvars parse error = %v, want one reported", broken.ParseErr)
+	}
+}
+
+func TestBackendLocation(t *testing.T) {
+	tests := []struct {
+		name        string
+		backendType string
+		settings    map[string]string
+		want        string
+	}{
+		{
+			name:     "s3 guessed",
+			settings: map[string]string{"bucket": "states", "key": "dev/terraform.tfstate", "region": "eu-west-1"},
+			want:     "s3://states/dev/terraform.tfstate (eu-west-1)",
+		},
+		{
+			name:        "s3 workspaces",
+			backendType: "s3",
+			settings:    map[string]string{"bucket": "states", "key": "app.tfstate", "workspace_key_prefix": "env"},
+			want:        "s3://states/app.tfstate, workspaces under env/",
+		},
+		{name: "s3 without bucket", backendType: "s3", settings: map[string]string{"key": "app.tfstate"}},
+		{name: "gcs guessed", settings: map[string]string{"bucket": "states", "prefix": "app/"}, want: "gs://states/app/<workspace>.tfstate"},
+		{
+			name:     "azurerm guessed",
+			settings: map[string]string{"storage_account_name": "tfstate", "container_name": "states", "key": "dev.tfstate"},
+			want:     "azurerm://tfstate/states/dev.tfstate",
+		},
+		{name: "local guessed", settings: map[string]string{"path": "state/dev.tfstate"}, want: "state/dev.tfstate"},
+		{name: "consul", backendType: "consul", settings: map[string]string{"address": "consul:8500", "path": "tf/dev"}, want: "consul://consul:8500/tf/dev"},
+		{name: "unknown settings", settings: map[string]string{"region": "eu-west-1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BackendLocation(tt.backendType, tt.settings); got != tt.want {
+				t.Errorf("BackendLocation() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchBackendsForEnv(t *testing.T) {
+	backends := []BackendVarFile{
+		{Name: "backend.tfvars"},
+		{Name: "backend_dev.tfvars", EnvName: "dev"},
+		{Name: "backend_prod.tfvars", EnvName: "prod"},
+		{Name: "eu/backend_prod.tfvars", EnvName: "eu-west-1/prod"},
+	}
+
+	tests := []struct {
+		envName string
+		want    []string
+	}{
+		{envName: "dev", want: []string{"backend_dev.tfvars"}},
+		{envName: "eu-west-1/prod", want: []string{"eu/backend_prod.tfvars"}},
+		{envName: "eu-west-2/prod", want: []string{"backend_prod.tfvars"}},
+		{envName: "staging", want: []string{"backend.tfvars"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.envName, func(t *testing.T) {
+			var got []string
+			for _, backend := range MatchBackendsForEnv(tt.envName, backends) {
+				got = append(got, backend.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MatchBackendsForEnv(%q) = %v, want %v", tt.envName, got, tt.want)
+			}
+		})
+	}
+	if got := MatchBackendsForEnv("dev", nil); len(got) != 0 {
+		t.Errorf("MatchBackendsForEnv() without backends = %v, want none", got)
+	}
+}
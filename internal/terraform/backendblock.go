@@ -0,0 +1,148 @@
+// Package terraform provides functionality for detecting, discovering,
+// and interacting with Terraform projects and resources.
+//
+// This file contains backend block parsing from the project's sources:
+// - ParseDeclaredBackend: Read the terraform { backend "x" {} } or cloud {} block
+// - MissingBackendKeys: List required backend settings set neither in the block, the backend file nor -backend-config
+// - BackendTypeMismatch: Compare the declared backend type with the initialized one
+package terraform
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// DeclaredBackend is the backend declared in the project's .tf files.
+type DeclaredBackend struct {
+	Type     string            // backend label (e.g., "s3"), or "cloud" for a cloud {} block
+	Settings map[string]string // literal attributes set in the block (partial configuration)
+	File     string            // file the block is declared in (e.g., "backend.tf")
+}
+
+// requiredBackendKeys lists the settings each backend can't work without.
+// Anything missing from the block must come from the -backend-config file.
+var requiredBackendKeys = map[string][]string{
+	"s3":         {"bucket", "key", "region"},
+	"gcs":        {"bucket"},
+	"azurerm":    {"storage_account_name", "container_name", "key"},
+	"consul":     {"path"},
+	"cos":        {"bucket"},
+	"oss":        {"bucket"},
+	"http":       {"address"},
+	"pg":         {"conn_str"},
+	"kubernetes": {"secret_suffix"},
+	"remote":     {"organization"},
+	"cloud":      {"organization"},
+}
+
+// backendKeyEnvVars lists environment variables that can stand in for a required setting.
+var backendKeyEnvVars = map[string][]string{
+	"s3.region":          {"AWS_REGION", "AWS_DEFAULT_REGION"},
+	"pg.conn_str":        {"PG_CONN_STR"},
+	"cloud.organization": {"TF_CLOUD_ORGANIZATION"},
+}
+
+// ParseDeclaredBackend reads the backend or cloud block from the project's terraform blocks.
+// Returns nil when the project doesn't declare one (Terraform then uses the local backend).
+func ParseDeclaredBackend(projectPath string) *DeclaredBackend {
+	for _, tfBlock := range terraformBlocks(parseProjectSources(projectPath)) {
+		for _, block := range tfBlock.Body.Blocks {
+			switch {
+			case block.Type == "backend" && len(block.Labels) == 1:
+				return &DeclaredBackend{
+					Type:     block.Labels[0],
+					Settings: literalSettings(block.Body, ""),
+					File:     filepath.Base(block.Range().Filename),
+				}
+			case block.Type == "cloud":
+				settings := literalSettings(block.Body, "")
+				for _, nested := range block.Body.Blocks {
+					for key, value := range literalSettings(nested.Body, nested.Type+".") {
+						settings[key] = value
+					}
+				}
+				return &DeclaredBackend{
+					Type:     "cloud",
+					Settings: settings,
+					File:     filepath.Base(block.Range().Filename),
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// literalSettings returns the string, number and bool attributes of a block body.
+// Keys are prefixed with prefix (e.g., "workspaces." for nested blocks).
+func literalSettings(body *hclsyntax.Body, prefix string) map[string]string {
+	settings := make(map[string]string)
+	for name, attr := range body.Attributes {
+		value, diags := attr.Expr.Value(nil)
+		if diags.HasErrors() || !value.IsKnown() || value.IsNull() {
+			continue
+		}
+		switch value.Type() {
+		case cty.String:
+			settings[prefix+name] = value.AsString()
+		case cty.Number, cty.Bool:
+			settings[prefix+name] = FormatValue(value, 0)
+		}
+	}
+	return settings
+}
+
+// MissingBackendKeys lists the required settings of the declared backend that are set
+// neither in the backend block, nor in the given backend file, nor in the extra
+// -backend-config key=value pairs, nor through env (the "KEY=VALUE" environment init runs with).
+func MissingBackendKeys(declared *DeclaredBackend, backend BackendVarFile, extraConfig []string, env []string) []string {
+	if declared == nil {
+		return nil
+	}
+
+	fileSettings := backend.StringSettings()
+	for _, pair := range extraConfig {
+		if key, value, ok := splitKeyValue(pair); ok && value != "" {
+			fileSettings[key] = value
+		}
+	}
+	var missing []string
+	for _, key := range requiredBackendKeys[declared.Type] {
+		if declared.Settings[key] != "" || fileSettings[key] != "" {
+			continue
+		}
+		fromEnv := false
+		for _, envVar := range backendKeyEnvVars[declared.Type+"."+key] {
+			if envValue(env, envVar) != "" {
+				fromEnv = true
+			}
+		}
+		if !fromEnv {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// BackendTypeMismatch returns a warning when the initialized backend type differs from the
+// declared one (init with -reconfigure or -migrate-state is needed), or "" when they agree.
+func BackendTypeMismatch(state BackendState) string {
+	if !state.IsInitialized || state.BackendType == "" {
+		return ""
+	}
+
+	declaredType := "local"
+	source := "no backend block"
+	if state.Declared != nil {
+		declaredType = state.Declared.Type
+		source = state.Declared.File
+	}
+	if declaredType == state.BackendType {
+		return ""
+	}
+	return "Declared backend \"" + declaredType + "\" (" + source + ") differs from the initialized \"" +
+		state.BackendType + "\" backend, init again with -reconfigure or -migrate-state"
+}
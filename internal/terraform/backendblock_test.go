@@ -0,0 +1,40 @@
+package terraform
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestMissingBackendKeys(t *testing.T) {
+	declared := &DeclaredBackend{Type: "s3", Settings: map[string]string{"bucket": "states"}}
+	backend := BackendVarFile{Settings: map[string]cty.Value{"key": cty.StringVal("dev/terraform.tfstate")}}
+
+	tests := []struct {
+		name     string
+		declared *DeclaredBackend
+		backend  BackendVarFile
+		extra    []string
+		env      []string
+		want     []string
+	}{
+		{name: "no backend declared", declared: nil, backend: backend},
+		{name: "from the block and the file", declared: declared, backend: backend, want: []string{"region"}},
+		{name: "from -backend-config pairs", declared: declared, backend: backend, extra: []string{"region = eu-west-1"}},
+		{name: "empty -backend-config value", declared: declared, backend: backend, extra: []string{"region="}, want: []string{"region"}},
+		{name: "from the environment", declared: declared, backend: backend, env: []string{"AWS_DEFAULT_REGION=eu-west-1"}},
+		{name: "empty in the environment", declared: declared, backend: backend, env: []string{"AWS_REGION=eu-west-1", "AWS_REGION="}, want: []string{"region"}},
+		{name: "nothing set", declared: &DeclaredBackend{Type: "s3"}, want: []string{"bucket", "key", "region"}},
+		{name: "backend without required keys", declared: &DeclaredBackend{Type: "local"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MissingBackendKeys(tt.declared, tt.backend, tt.extra, tt.env)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MissingBackendKeys() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// Package terraform provides functionality for detecting, discovering,
+// and interacting with Terraform projects and resources.
+//
+// This file contains the shared command plumbing used by RunInit, RunPlan, RunApply and LoadGraph:
+// - CommandContext: Which binary to run, where, and through which wrapper
+// - run: Start a command with streamed output
+// - output: Run a command to completion and capture its output
+package terraform
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Nicolas-Rigaudy/lazytf/internal/executor"
+)
+
+// CommandContext holds what every terraform command needs to know about the project it runs in.
+type CommandContext struct {
+	ProjectPath string
+	Engine      Engine   // terraform or tofu, Terraform if empty
+	Binary      string   // executable to run, the engine's PATH binary if empty
+	Terragrunt  bool     // run through terragrunt instead of calling the engine directly
+	RunAll      bool     // with Terragrunt, run the command on every unit below ProjectPath
+	Env         []string // extra environment variables (e.g. "AWS_PROFILE=dev")
+}
+
+// envValue returns the value of a variable in a "KEY=VALUE" environment, later entries win.
+func envValue(env []string, name string) string {
+	for i := len(env) - 1; i >= 0; i-- {
+		if value, ok := strings.CutPrefix(env[i], name+"="); ok {
+			return value
+		}
+	}
+	return ""
+}
+
+// binary returns the executable to run, defaulting to the engine binary found in PATH.
+func (c CommandContext) binary() string {
+	if c.Binary != "" {
+		return c.Binary
+	}
+	if c.Engine != "" {
+		return c.Engine.Binary()
+	}
+	return EngineTerraform.Binary()
+}
+
+// commandLine returns the executable, arguments and extra environment used to run
+// the given engine arguments (e.g. "init", "-upgrade").
+// With Terragrunt, arguments are passed through `terragrunt [run-all] ...` and terragrunt
+// is pointed at the resolved engine binary.
+func (c CommandContext) commandLine(args []string) (string, []string, []string) {
+	if !c.Terragrunt {
+		return c.binary(), args, c.Env
+	}
+
+	if c.RunAll {
+		args = append([]string{"run-all"}, args...)
+	}
+
+	// Both the current (TG_*) and legacy (TERRAGRUNT_*) variable names are set
+	// so older terragrunt releases pick them up too
+	env := []string{
+		"TG_TF_PATH=" + c.binary(),
+		"TERRAGRUNT_TFPATH=" + c.binary(),
+		"TG_NON_INTERACTIVE=true",
+		"TERRAGRUNT_NON_INTERACTIVE=true",
+	}
+	return "terragrunt", args, append(env, c.Env...)
+}
+
+// run starts the command and streams its output to the UI.
+func (c CommandContext) run(args []string) tea.Cmd {
+	name, args, env := c.commandLine(args)
+	return executor.ExecuteStreamingWithEnv(name, args, c.ProjectPath, env)
+}
+
+// output runs the command to completion and returns its stdout.
+// Used for commands whose output is parsed rather than displayed (e.g. graph).
+func (c CommandContext) output(args []string) ([]byte, error) {
+	name, args, env := c.commandLine(args)
+	cmd := exec.Command(name, args...)
+	cmd.Dir = c.ProjectPath
+	cmd.Env = append(os.Environ(), env...)
+
+	output, err := cmd.Output()
+	if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+		return output, fmt.Errorf("%s %s failed: %s", name, strings.Join(args, " "), strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return output, err
+}
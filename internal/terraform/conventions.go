@@ -0,0 +1,124 @@
+// Package terraform provides functionality for detecting, discovering,
+// and interacting with Terraform projects and resources.
+//
+// This file contains naming convention matching (see config.NamingConventions):
+// - MatchGlob: Match a project-relative path against a glob with ** support
+// - ExtractEnv: Extract the environment name of a file using a template or regex
+// - globRoot: The directory a glob can match files under
+package terraform
+
+import (
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// globToRegexp converts a glob into an anchored regular expression.
+// "**/" matches any number of directories (including none), "*" and "?" stay within a directory.
+func globToRegexp(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			b.WriteString("[^/]*")
+		case pattern[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+// MatchGlob reports whether a project-relative path matches a glob.
+// Example: "variables/backend/**/*.tfvars" matches "variables/backend/aws/backend_dev.tfvars"
+func MatchGlob(pattern, relPath string) bool {
+	re, err := globToRegexp(filepath.ToSlash(pattern))
+	if err != nil {
+		return false
+	}
+	return re.MatchString(filepath.ToSlash(relPath))
+}
+
+// globRoot returns the directory part of a glob before its first wildcard,
+// i.e. the only directory that needs to be walked to find matches.
+// Example: "variables/backend/**/*.tfvars" -> "variables/backend"
+func globRoot(pattern string) string {
+	var static []string
+	for _, segment := range strings.Split(filepath.ToSlash(pattern), "/") {
+		if strings.ContainsAny(segment, "*?") {
+			break
+		}
+		static = append(static, segment)
+	}
+	// The last static segment is the file name when the glob has no wildcard
+	if len(static) == len(strings.Split(filepath.ToSlash(pattern), "/")) {
+		static = static[:len(static)-1]
+	}
+	if len(static) == 0 {
+		return "."
+	}
+	return path.Join(static...)
+}
+
+// envPattern compiles an env pattern: a regular expression with an "env" group,
+// or a template where {env} stands for the environment name and * for any text.
+func envPattern(pattern string) (*regexp.Regexp, error) {
+	if strings.Contains(pattern, "(?P<env>") {
+		return regexp.Compile(pattern)
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	for i, part := range strings.Split(pattern, "{env}") {
+		if i > 0 {
+			b.WriteString("(?P<env>.+)")
+		}
+		for j, literal := range strings.Split(part, "*") {
+			if j > 0 {
+				b.WriteString("[^/]*")
+			}
+			b.WriteString(regexp.QuoteMeta(literal))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+// ExtractEnv extracts the environment name of a file from its project-relative path.
+// Patterns containing a "/" are matched against the whole path, others against the file name.
+// Returns false when the pattern is empty, invalid or doesn't match.
+// Examples:
+//   - "backend_{env}.tfvars", "variables/backend/backend_dev.tfvars" -> "dev"
+//   - "{env}/backend.hcl", "eu-west-1/prod/backend.hcl" -> "eu-west-1/prod"
+//   - "^state-(?P<env>[a-z]+)\\.tfvars$", "state-int.tfvars" -> "int"
+func ExtractEnv(pattern, relPath string) (string, bool) {
+	if pattern == "" {
+		return "", false
+	}
+	re, err := envPattern(pattern)
+	if err != nil {
+		return "", false
+	}
+
+	subject := filepath.ToSlash(relPath)
+	if !strings.Contains(pattern, "/") {
+		subject = path.Base(subject)
+	}
+
+	match := re.FindStringSubmatch(subject)
+	index := re.SubexpIndex("env")
+	if match == nil || index < 0 || match[index] == "" {
+		return "", false
+	}
+	return match[index], true
+}
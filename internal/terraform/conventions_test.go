@@ -0,0 +1,80 @@
+package terraform
+
+import "testing"
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*.tfvars", "dev.tfvars", true},
+		{"*.tfvars", "variables/dev.tfvars", false},
+		{"variables/*.tfvars", "variables/dev.tfvars", true},
+		{"variables/backend/**/*.tfvars", "variables/backend/aws/backend_dev.tfvars", true},
+		{"variables/backend/**/*.tfvars", "variables/backend/backend_dev.tfvars", true},
+		{"variables/backend/**/*.tfvars", "variables/backend_dev.tfvars", false},
+		{"**/backend.hcl", "eu-west-1/prod/backend.hcl", true},
+		{"**/backend.hcl", "backend.hcl", true},
+		{"env/**", "env/a/b/c.tfvars", true},
+		{"backend_?.tfvars", "backend_a.tfvars", true},
+		{"backend_?.tfvars", "backend_ab.tfvars", false},
+		{"backend.(dev).tfvars", "backend.(dev).tfvars", true},
+		{"backend.tfvars", "backendXtfvars", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.path, func(t *testing.T) {
+			if got := MatchGlob(tt.pattern, tt.path); got != tt.want {
+				t.Errorf("MatchGlob(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGlobRoot(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{"variables/backend/**/*.tfvars", "variables/backend"},
+		{"*.tfvars", "."},
+		{"variables/backend.tfvars", "variables"},
+		{"backend.tfvars", "."},
+	}
+
+	for _, tt := range tests {
+		if got := globRoot(tt.pattern); got != tt.want {
+			t.Errorf("globRoot(%q) = %q, want %q", tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestExtractEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		path    string
+		want    string
+		ok      bool
+	}{
+		{"template on the file name", "backend_{env}.tfvars", "variables/backend/backend_dev.tfvars", "dev", true},
+		{"template with a wildcard", "backend_{env}.*.tfvars", "backend_prod.s3.tfvars", "prod", true},
+		{"template on the whole path", "{env}/backend.hcl", "eu-west-1/prod/backend.hcl", "eu-west-1/prod", true},
+		{"regular expression", `^state-(?P<env>[a-z]+)\.tfvars$`, "state-int.tfvars", "int", true},
+		{"no match", "backend_{env}.tfvars", "variables/dev.tfvars", "", false},
+		{"empty env", "backend_{env}.tfvars", "backend_.tfvars", "", false},
+		{"empty pattern", "", "backend_dev.tfvars", "", false},
+		{"invalid regular expression", `^(?P<env>[a-z+\.tfvars$`, "dev.tfvars", "", false},
+		{"regular expression without env group", "^backend.tfvars$", "backend.tfvars", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ExtractEnv(tt.pattern, tt.path)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("ExtractEnv(%q, %q) = %q, %v, want %q, %v", tt.pattern, tt.path, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
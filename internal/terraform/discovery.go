@@ -3,7 +3,8 @@
 //
 // This file contains all project discovery and detection logic:
 // - IsInitialized: Check if a directory has .terraform/
-// - IsTerraformProject: Check if a directory contains .tf files
+// - IsTerraformProject: Check if a directory contains .tf or .tofu files
+// - IsTerragruntProject (terragrunt.go): Check if a directory is a Terragrunt unit
 // - DetermineMode: Decide between single-project vs multi-project mode
 // - DiscoverProjects: Find all Terraform projects in search paths
 package terraform
@@ -11,7 +12,6 @@ package terraform
 import (
 	"os"
 	"path/filepath"
-	"strings"
 
 	"github.com/Nicolas-Rigaudy/lazytf/internal/config"
 )
@@ -27,7 +27,8 @@ func IsInitialized(path string) bool {
 	return info.IsDir()
 }
 
-// IsTerraformProject checks if a directory contains Terraform files (*.tf).
+// IsTerraformProject checks if a directory contains Terraform files (*.tf)
+// or OpenTofu files (*.tofu).
 // This is more permissive than IsInitialized - it finds uninitialized projects too.
 func IsTerraformProject(path string) (bool, error) {
 	entries, err := os.ReadDir(path)
@@ -35,7 +36,10 @@ func IsTerraformProject(path string) (bool, error) {
 		return false, err
 	}
 	for _, entry := range entries {
-		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".tf" {
+		if entry.IsDir() {
+			continue
+		}
+		if ext := filepath.Ext(entry.Name()); ext == ".tf" || ext == ".tofu" {
 			return true, nil
 		}
 	}
@@ -43,8 +47,8 @@ func IsTerraformProject(path string) (bool, error) {
 }
 
 // DetermineMode checks the current directory and decides which mode to run in.
-// Returns ModeSingleProject if current directory is an initialized TF project,
-// otherwise returns ModeMultiProject.
+// Returns ModeSingleProject if current directory is an initialized TF project
+// or a Terragrunt unit, otherwise returns ModeMultiProject.
 // If ModeSingleProject, also returns a Project struct with basic info.
 func DetermineMode() (Mode, *Project, error) {
 	workDir, err := os.Getwd()
@@ -52,13 +56,15 @@ func DetermineMode() (Mode, *Project, error) {
 		return ModeMultiProject, nil, err
 	}
 
-	if IsInitialized(workDir) {
+	if IsInitialized(workDir) || IsTerragruntProject(workDir) {
 		project := Project{
 			Name:             filepath.Base(workDir),
 			Path:             workDir,
-			IsInitialized:    true,
+			IsInitialized:    IsInitialized(workDir),
 			Workspaces:       nil,
 			CurrentWorkspace: "",
+			IsTerragrunt:     IsTerragruntProject(workDir),
+			IsStack:          IsTerragruntProject(workDir) && !IsTerragruntUnit(workDir),
 		}
 		return ModeSingleProject, &project, nil
 	}
@@ -66,22 +72,6 @@ func DetermineMode() (Mode, *Project, error) {
 	return ModeMultiProject, nil, nil
 }
 
-// ExpandPath expands a path that may contain ~ to the full home directory path.
-// Example: ~/Projects -> /home/username/Projects
-func ExpandPath(path string) (string, error) {
-	if !strings.HasPrefix(path, "~") {
-		return path, nil
-	}
-
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return "", err
-	}
-
-	expandedPath := strings.Replace(path, "~", homeDir, 1)
-	return expandedPath, nil
-}
-
 // shouldIgnore checks if a path matches any of the ignore patterns.
 func shouldIgnore(path string, ignorePatterns []string) bool {
 	for _, pattern := range ignorePatterns {
@@ -98,8 +88,13 @@ func shouldIgnore(path string, ignorePatterns []string) bool {
 func DiscoverProjects(cfg config.Config) ([]Project, error) {
 	var projects []Project
 
+	// A terragrunt.hcl without the signs of a unit is a stack if another one sits below it,
+	// a leaf unit otherwise: both are only known once the walk is done
+	var undecided []int
+	configBelow := make(map[string]bool)
+
 	for _, searchPath := range cfg.SearchPaths {
-		searchPath, err := ExpandPath(searchPath)
+		searchPath, err := config.ExpandHome(searchPath)
 		if err != nil {
 			return nil, err
 		}
@@ -121,6 +116,36 @@ func DiscoverProjects(cfg config.Config) ([]Project, error) {
 				return nil
 			}
 
+			// Terragrunt caches full module copies, never treat them as projects
+			if info.Name() == ".terragrunt-cache" {
+				return filepath.SkipDir
+			}
+
+			if IsTerragruntProject(path) {
+				markConfigAncestors(configBelow, path)
+
+				// Terragrunt units take precedence over the .tf files they may contain
+				if deploysTerragruntModule(path) {
+					projects = append(projects, Project{
+						Name:          filepath.Base(path),
+						Path:          path,
+						IsInitialized: IsInitialized(path),
+						IsTerragrunt:  true,
+					})
+					return filepath.SkipDir
+				}
+
+				// A root terragrunt.hcl only included by the units below it is a stack:
+				// listed for run-all, and the walk continues to find its units
+				undecided = append(undecided, len(projects))
+				projects = append(projects, Project{
+					Name:         filepath.Base(path),
+					Path:         path,
+					IsTerragrunt: true,
+				})
+				return nil
+			}
+
 			// Check if this directory is a TF project
 			isTF, err := IsTerraformProject(path)
 			if err != nil {
@@ -149,6 +174,14 @@ func DiscoverProjects(cfg config.Config) ([]Project, error) {
 		}
 	}
 
+	for _, i := range undecided {
+		if configBelow[projects[i].Path] {
+			projects[i].IsStack = true
+		} else {
+			projects[i].IsInitialized = IsInitialized(projects[i].Path)
+		}
+	}
+
 	// Remove duplicates and make project names unique
 	projects = makeProjectNamesUnique(projects)
 
@@ -0,0 +1,86 @@
+// Package terraform provides functionality for detecting, discovering,
+// and interacting with Terraform projects and resources.
+//
+// This file contains engine selection logic (Terraform vs OpenTofu):
+// - ParseEngine: Normalize an engine name from config
+// - HasTofuFiles: Check if a directory contains OpenTofu-specific .tofu files
+// - SelectEngine: Pick the engine for a project from config and project files
+package terraform
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Nicolas-Rigaudy/lazytf/internal/config"
+)
+
+// Engine identifies the CLI used to run commands for a project.
+type Engine string
+
+const (
+	EngineTerraform Engine = "terraform"
+	EngineOpenTofu  Engine = "tofu"
+)
+
+// ParseEngine normalizes an engine name from config.
+// Accepts "tofu" and "opentofu" for OpenTofu, "terraform" and "" for Terraform.
+// Unknown names fall back to Terraform with an error telling so.
+func ParseEngine(name string) (Engine, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "tofu", "opentofu":
+		return EngineOpenTofu, nil
+	case "terraform", "":
+		return EngineTerraform, nil
+	default:
+		return EngineTerraform, fmt.Errorf("unknown engine %q in config, using Terraform (expected \"terraform\" or \"tofu\")", name)
+	}
+}
+
+// Binary returns the executable name of the engine as found in PATH.
+func (e Engine) Binary() string {
+	if e == EngineOpenTofu {
+		return "tofu"
+	}
+	return "terraform"
+}
+
+// DisplayName returns the product name for UI display.
+func (e Engine) DisplayName() string {
+	if e == EngineOpenTofu {
+		return "OpenTofu"
+	}
+	return "Terraform"
+}
+
+// HasTofuFiles checks if a directory contains OpenTofu-specific files (*.tofu).
+func HasTofuFiles(path string) bool {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".tofu" {
+			return true
+		}
+	}
+	return false
+}
+
+// SelectEngine picks the engine for a project. Priority:
+//   - engine set for the project in config
+//   - OpenTofu if the project contains .tofu files
+//   - global engine from config
+//   - Terraform
+//
+// The error reports an unknown engine name in config, the engine is Terraform then.
+func SelectEngine(cfg config.Config, projectPath string) (Engine, error) {
+	if engine := cfg.ProjectSettings(projectPath).Engine; engine != "" {
+		return ParseEngine(engine)
+	}
+	if HasTofuFiles(projectPath) {
+		return EngineOpenTofu, nil
+	}
+	return ParseEngine(cfg.Engine)
+}
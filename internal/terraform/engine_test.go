@@ -0,0 +1,74 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/Nicolas-Rigaudy/lazytf/internal/config"
+)
+
+func TestParseEngine(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Engine
+		wantErr bool
+	}{
+		{name: "", want: EngineTerraform},
+		{name: "terraform", want: EngineTerraform},
+		{name: " Terraform ", want: EngineTerraform},
+		{name: "tofu", want: EngineOpenTofu},
+		{name: "OpenTofu", want: EngineOpenTofu},
+		{name: "terragrunt", want: EngineTerraform, wantErr: true},
+		{name: "tf", want: EngineTerraform, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseEngine(tt.name)
+			if got != tt.want || (err != nil) != tt.wantErr {
+				t.Errorf("ParseEngine(%q) = %q, %v, want %q with error %v", tt.name, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSelectEngine(t *testing.T) {
+	plain := t.TempDir()
+	tofu := t.TempDir()
+	writeFile(t, tofu, "main.tofu", "", 0o644)
+
+	tests := []struct {
+		name        string
+		cfg         config.Config
+		projectPath string
+		want        Engine
+		wantErr     bool
+	}{
+		{name: "default", projectPath: plain, want: EngineTerraform},
+		{name: "global engine", cfg: config.Config{Engine: "tofu"}, projectPath: plain, want: EngineOpenTofu},
+		{name: ".tofu files", projectPath: tofu, want: EngineOpenTofu},
+		{name: ".tofu files win over the global engine", cfg: config.Config{Engine: "terraform"}, projectPath: tofu, want: EngineOpenTofu},
+		{
+			name:        "project engine wins",
+			cfg:         config.Config{Engine: "tofu", Projects: map[string]config.ProjectConfig{tofu: {Engine: "terraform"}}},
+			projectPath: tofu,
+			want:        EngineTerraform,
+		},
+		{name: "unknown global engine", cfg: config.Config{Engine: "tf"}, projectPath: plain, want: EngineTerraform, wantErr: true},
+		{
+			name:        "unknown project engine",
+			cfg:         config.Config{Projects: map[string]config.ProjectConfig{plain: {Engine: "open-tofu"}}},
+			projectPath: plain,
+			want:        EngineTerraform,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SelectEngine(tt.cfg, tt.projectPath)
+			if got != tt.want || (err != nil) != tt.wantErr {
+				t.Errorf("SelectEngine() = %q, %v, want %q with error %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
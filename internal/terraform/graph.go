@@ -0,0 +1,239 @@
+// Package terraform provides functionality for detecting, discovering,
+// and interacting with Terraform projects and resources.
+//
+// This file contains dependency graph logic based on `terraform graph`:
+// - LoadGraph: Run `terraform graph` and parse its output
+// - ParseDOTGraph: Build a resource-level dependency graph from DOT output
+// - FormatDependencyTree: Render what a resource depends on and what depends on it
+package terraform
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Graph is a resource-level dependency graph.
+// Intermediate nodes (variables, locals, outputs, providers) are collapsed, so an edge
+// resource -> local -> resource becomes a direct resource -> resource edge.
+type Graph struct {
+	Nodes      []string            // resource and module addresses, sorted
+	DependsOn  map[string][]string // node -> nodes it depends on
+	Dependents map[string][]string // node -> nodes that depend on it
+}
+
+// GraphOptions configures LoadGraph.
+type GraphOptions struct {
+	Type string // "" for the default graph, or "plan", "plan-refresh-only", "plan-destroy", "apply"
+}
+
+var (
+	// "[root] aws_instance.web (expand)" -> "[root] aws_vpc.main (expand)"
+	dotEdgePattern = regexp.MustCompile(`^\s*"([^"]+)"\s*->\s*"([^"]+)"`)
+	// "[root] aws_instance.web (expand)" [label = "aws_instance.web", shape = "box"]
+	dotNodePattern = regexp.MustCompile(`^\s*"([^"]+)"\s*\[`)
+)
+
+// LoadGraph runs `terraform graph` for the project and parses the result.
+func LoadGraph(ctx CommandContext, options GraphOptions) (*Graph, error) {
+	args := []string{"graph"}
+	if options.Type != "" {
+		args = append(args, "-type="+options.Type)
+	}
+
+	output, err := ctx.output(args)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDOTGraph(string(output)), nil
+}
+
+// ParseDOTGraph parses the DOT output of `terraform graph`.
+// Both the legacy format ("[root] aws_vpc.main (expand)") and the simplified
+// format of Terraform 1.7+ ("aws_vpc.main") are supported.
+// An edge "a" -> "b" means a depends on b.
+func ParseDOTGraph(dot string) *Graph {
+	nodes := make(map[string]bool)
+	edges := make(map[string][]string)
+
+	for _, line := range strings.Split(dot, "\n") {
+		if match := dotEdgePattern.FindStringSubmatch(line); match != nil {
+			from, to := normalizeGraphNode(match[1]), normalizeGraphNode(match[2])
+			if from == "" || to == "" || from == to {
+				continue
+			}
+			nodes[from] = true
+			nodes[to] = true
+			edges[from] = append(edges[from], to)
+			continue
+		}
+		if match := dotNodePattern.FindStringSubmatch(line); match != nil {
+			if node := normalizeGraphNode(match[1]); node != "" {
+				nodes[node] = true
+			}
+		}
+	}
+
+	graph := &Graph{
+		DependsOn:  make(map[string][]string),
+		Dependents: make(map[string][]string),
+	}
+
+	for node := range nodes {
+		if !isResourceNode(node) {
+			continue
+		}
+		graph.Nodes = append(graph.Nodes, node)
+
+		for _, dep := range resourceDependencies(node, edges) {
+			graph.DependsOn[node] = append(graph.DependsOn[node], dep)
+			graph.Dependents[dep] = append(graph.Dependents[dep], node)
+		}
+	}
+
+	sort.Strings(graph.Nodes)
+	for _, deps := range graph.DependsOn {
+		sort.Strings(deps)
+	}
+	for _, deps := range graph.Dependents {
+		sort.Strings(deps)
+	}
+
+	return graph
+}
+
+// normalizeGraphNode strips graph decorations from a node name.
+// Example: "[root] module.vpc.aws_subnet.a (expand)" -> "module.vpc.aws_subnet.a"
+func normalizeGraphNode(name string) string {
+	name = strings.TrimPrefix(name, "[root] ")
+	for _, suffix := range []string{" (expand)", " (close)", " (prepare state)", " (destroy)"} {
+		name = strings.TrimSuffix(name, suffix)
+	}
+	return strings.TrimSpace(name)
+}
+
+// isResourceNode reports whether a graph node is a resource, data source or module call.
+// Variables, locals, outputs, providers and graph meta nodes are not.
+func isResourceNode(node string) bool {
+	if node == "root" || strings.HasPrefix(node, "provider[") || strings.HasPrefix(node, "meta.") {
+		return false
+	}
+
+	// Strip module path prefixes ("module.a.module.b.aws_x.y" -> "aws_x.y")
+	rest := node
+	for strings.HasPrefix(rest, "module.") {
+		parts := strings.SplitN(rest, ".", 3)
+		if len(parts) < 3 {
+			return true // a module call itself, e.g. "module.vpc"
+		}
+		rest = parts[2]
+	}
+
+	if strings.HasPrefix(rest, "data.") {
+		return true
+	}
+
+	first := strings.SplitN(rest, ".", 2)[0]
+	switch first {
+	case "var", "local", "output", "meta", "provider", "check", "ephemeral":
+		return false
+	}
+	return strings.Contains(rest, ".") && !strings.HasPrefix(first, "provider[")
+}
+
+// resourceDependencies follows edges from node through non-resource nodes and
+// returns the first resource nodes reached, i.e. its resource-level dependencies.
+func resourceDependencies(node string, edges map[string][]string) []string {
+	var deps []string
+	seen := map[string]bool{node: true}
+	queue := append([]string{}, edges[node]...)
+
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+		if seen[next] {
+			continue
+		}
+		seen[next] = true
+
+		if isResourceNode(next) {
+			deps = append(deps, next)
+			continue
+		}
+		queue = append(queue, edges[next]...)
+	}
+
+	return deps
+}
+
+// CountReachable returns how many nodes are reachable from node following the given edges.
+// With Dependents this is the blast radius of changing node.
+func CountReachable(node string, edges map[string][]string) int {
+	seen := map[string]bool{node: true}
+	queue := append([]string{}, edges[node]...)
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+		if seen[next] {
+			continue
+		}
+		seen[next] = true
+		queue = append(queue, edges[next]...)
+	}
+	return len(seen) - 1
+}
+
+// FormatDependencyTree renders an ASCII tree of what node depends on and what depends on it.
+//
+//	aws_subnet.a
+//	├── depends on (1)
+//	│   └── aws_vpc.main
+//	└── required by (2)
+//	    ├── aws_instance.web
+//	    │   └── aws_lb_target_group_attachment.web
+//	    └── aws_route_table_association.a
+func FormatDependencyTree(graph *Graph, node string) string {
+	var b strings.Builder
+	b.WriteString(node + "\n")
+
+	dependsOn := CountReachable(node, graph.DependsOn)
+	requiredBy := CountReachable(node, graph.Dependents)
+
+	b.WriteString("├── depends on (" + strconv.Itoa(dependsOn) + ")\n")
+	writeSubtree(&b, graph.DependsOn, node, "│   ", map[string]bool{node: true})
+
+	b.WriteString("└── required by (" + strconv.Itoa(requiredBy) + ")\n")
+	writeSubtree(&b, graph.Dependents, node, "    ", map[string]bool{node: true})
+
+	return b.String()
+}
+
+// writeSubtree writes the children of node following edges, recursively.
+// Each node is expanded once; later occurrences (shared dependencies and cycles)
+// are marked "(see above)" so diamond-shaped graphs don't blow up.
+func writeSubtree(b *strings.Builder, edges map[string][]string, node, prefix string, expanded map[string]bool) {
+	children := edges[node]
+	if len(children) == 0 {
+		b.WriteString(prefix + "└── (none)\n")
+		return
+	}
+
+	for i, child := range children {
+		branch, indent := "├── ", "│   "
+		if i == len(children)-1 {
+			branch, indent = "└── ", "    "
+		}
+
+		if expanded[child] && len(edges[child]) > 0 {
+			b.WriteString(prefix + branch + child + " (see above)\n")
+			continue
+		}
+		b.WriteString(prefix + branch + child + "\n")
+
+		if len(edges[child]) > 0 {
+			expanded[child] = true
+			writeSubtree(b, edges, child, prefix+indent, expanded)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package terraform
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDOTGraph(t *testing.T) {
+	tests := []struct {
+		name       string
+		dot        string
+		nodes      []string
+		dependsOn  map[string][]string
+		dependents map[string][]string
+	}{
+		{
+			name: "legacy format",
+			dot: `digraph {
+	compound = "true"
+	subgraph "root" {
+		"[root] aws_instance.web (expand)" [label = "aws_instance.web", shape = "box"]
+		"[root] aws_vpc.main (expand)" [label = "aws_vpc.main", shape = "box"]
+		"[root] provider[\"registry.terraform.io/hashicorp/aws\"]" [label = "provider", shape = "diamond"]
+		"[root] aws_instance.web (expand)" -> "[root] aws_vpc.main (expand)"
+		"[root] aws_vpc.main (expand)" -> "[root] provider[\"registry.terraform.io/hashicorp/aws\"]"
+	}
+}`,
+			nodes:      []string{"aws_instance.web", "aws_vpc.main"},
+			dependsOn:  map[string][]string{"aws_instance.web": {"aws_vpc.main"}},
+			dependents: map[string][]string{"aws_vpc.main": {"aws_instance.web"}},
+		},
+		{
+			name: "simplified format",
+			dot: `digraph G {
+  "aws_subnet.a" [label="aws_subnet.a"];
+  "aws_vpc.main" [label="aws_vpc.main"];
+  "aws_subnet.a" -> "aws_vpc.main";
+}`,
+			nodes:      []string{"aws_subnet.a", "aws_vpc.main"},
+			dependsOn:  map[string][]string{"aws_subnet.a": {"aws_vpc.main"}},
+			dependents: map[string][]string{"aws_vpc.main": {"aws_subnet.a"}},
+		},
+		{
+			name: "locals and variables are collapsed",
+			dot: `"aws_instance.web" -> "local.subnet_id"
+"local.subnet_id" -> "aws_subnet.a"
+"local.subnet_id" -> "var.env"`,
+			nodes:      []string{"aws_instance.web", "aws_subnet.a"},
+			dependsOn:  map[string][]string{"aws_instance.web": {"aws_subnet.a"}},
+			dependents: map[string][]string{"aws_subnet.a": {"aws_instance.web"}},
+		},
+		{
+			name: "modules and data sources",
+			dot: `"module.app.aws_instance.web" -> "data.aws_ami.ubuntu"
+"module.app" -> "module.network"`,
+			nodes: []string{"data.aws_ami.ubuntu", "module.app", "module.app.aws_instance.web", "module.network"},
+			dependsOn: map[string][]string{
+				"module.app":                  {"module.network"},
+				"module.app.aws_instance.web": {"data.aws_ami.ubuntu"},
+			},
+			dependents: map[string][]string{
+				"data.aws_ami.ubuntu": {"module.app.aws_instance.web"},
+				"module.network":      {"module.app"},
+			},
+		},
+		{
+			name:       "no resources",
+			dot:        `"output.id" -> "var.env"`,
+			dependsOn:  map[string][]string{},
+			dependents: map[string][]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			graph := ParseDOTGraph(tt.dot)
+			if !reflect.DeepEqual(graph.Nodes, tt.nodes) {
+				t.Errorf("Nodes = %v, want %v", graph.Nodes, tt.nodes)
+			}
+			if !reflect.DeepEqual(graph.DependsOn, tt.dependsOn) {
+				t.Errorf("DependsOn = %v, want %v", graph.DependsOn, tt.dependsOn)
+			}
+			if !reflect.DeepEqual(graph.Dependents, tt.dependents) {
+				t.Errorf("Dependents = %v, want %v", graph.Dependents, tt.dependents)
+			}
+		})
+	}
+}
@@ -2,36 +2,114 @@ package terraform
 
 import (
 	"fmt"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+)
 
-	"github.com/Nicolas-Rigaudy/lazytf/internal/executor"
+// Lockfile modes accepted by -lockfile
+const (
+	LockfileDefault  = ""
+	LockfileReadonly = "readonly"
 )
 
 type InitOptions struct {
 	BackendConfigFile BackendVarFile
+	BackendConfig     []string // extra -backend-config key=value pairs
 	Reconfigure       bool
+	MigrateState      bool
+	ForceCopy         bool // -force-copy, answer yes to the state migration prompts (implies -migrate-state)
 	Upgrade           bool
+	NoBackend         bool   // -backend=false, skip backend initialization
+	PluginDir         string // -plugin-dir, use providers from this directory only
+	LockfileMode      string // -lockfile, LockfileDefault or LockfileReadonly
 	Input             bool
 }
 
-func RunInit(projectPath string, options InitOptions) tea.Cmd {
+// Validate reports option combinations terraform would reject.
+func (o InitOptions) Validate() error {
+	if o.Reconfigure && o.MigrateState {
+		return fmt.Errorf("-reconfigure and -migrate-state cannot be used together")
+	}
+	if o.NoBackend && o.MigrateState {
+		return fmt.Errorf("-migrate-state requires the backend to be initialized")
+	}
+	if o.LockfileMode != LockfileDefault && o.LockfileMode != LockfileReadonly {
+		return fmt.Errorf("unsupported lockfile mode %q", o.LockfileMode)
+	}
+	for _, pair := range o.BackendConfig {
+		if _, _, ok := splitKeyValue(pair); !ok {
+			return fmt.Errorf("backend config %q is not a key=value pair", pair)
+		}
+	}
+	return nil
+}
+
+// InitArgs builds the init command line arguments for the given options.
+func InitArgs(options InitOptions) []string {
 	args := []string{"init"}
 
-	if options.BackendConfigFile.Name != "" {
-		args = append(args, fmt.Sprintf("-backend-config=%s", options.BackendConfigFile.FullPath))
+	if options.NoBackend {
+		args = append(args, "-backend=false")
+	} else {
+		if options.BackendConfigFile.Name != "" {
+			args = append(args, fmt.Sprintf("-backend-config=%s", options.BackendConfigFile.FullPath))
+		}
+		for _, pair := range options.BackendConfig {
+			args = append(args, fmt.Sprintf("-backend-config=%s", pair))
+		}
 	}
 
 	if options.Reconfigure {
 		args = append(args, "-reconfigure")
 	}
 
+	if options.MigrateState {
+		args = append(args, "-migrate-state")
+	}
+
+	if options.ForceCopy {
+		args = append(args, "-force-copy")
+	}
+
 	if options.Upgrade {
 		args = append(args, "-upgrade")
 	}
 
+	if options.PluginDir != "" {
+		args = append(args, fmt.Sprintf("-plugin-dir=%s", options.PluginDir))
+	}
+
+	if options.LockfileMode != LockfileDefault {
+		args = append(args, fmt.Sprintf("-lockfile=%s", options.LockfileMode))
+	}
+
 	if !options.Input {
 		args = append(args, "-input=false")
 	}
-	return executor.ExecuteStreaming("terraform", args, projectPath)
+	return args
+}
+
+func RunInit(ctx CommandContext, options InitOptions) tea.Cmd {
+	return ctx.run(InitArgs(options))
+}
+
+// StorableBackendConfig returns the -backend-config pairs that can be saved in the config file,
+// dropping the ones whose value looks like a secret (see LooksLikeSecret).
+func StorableBackendConfig(pairs []string) []string {
+	var storable []string
+	for _, pair := range pairs {
+		if key, value, ok := splitKeyValue(pair); ok && LooksLikeSecret(key, value) {
+			continue
+		}
+		storable = append(storable, pair)
+	}
+	return storable
+}
+
+// splitKeyValue splits a "key=value" pair, trimming spaces around both parts.
+func splitKeyValue(pair string) (string, string, bool) {
+	key, value, ok := strings.Cut(pair, "=")
+	key = strings.TrimSpace(key)
+	return key, strings.TrimSpace(value), ok && key != ""
 }
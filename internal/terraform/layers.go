@@ -0,0 +1,166 @@
+// Package terraform provides functionality for detecting, discovering,
+// and interacting with Terraform projects and resources.
+//
+// This file contains var file layering logic (e.g. common.tfvars + dev.tfvars):
+// - ResolveVarFileLayers: Build the ordered list of var files for an environment
+// - EnvironmentVarFiles: Drop shared layer and auto-loaded files from the environment list
+// - FormatVarFileLayers: Format the effective var file list for UI display
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EnvLayer is the layer rule placeholder for the environment name.
+// A rule that is exactly EnvLayer stands for the environment's own var file.
+const EnvLayer = "{env}"
+
+// autoLoadedVarFiles returns the var files Terraform loads without -var-file,
+// in the order Terraform applies them: terraform.tfvars, terraform.tfvars.json,
+// then *.auto.tfvars and *.auto.tfvars.json in lexical order.
+func autoLoadedVarFiles(projectPath string) []string {
+	var paths []string
+
+	for _, name := range []string{"terraform.tfvars", "terraform.tfvars.json"} {
+		path := filepath.Join(projectPath, name)
+		if _, err := os.Stat(path); err == nil {
+			paths = append(paths, path)
+		}
+	}
+
+	entries, err := os.ReadDir(projectPath)
+	if err != nil {
+		return paths
+	}
+	var autoFiles []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if strings.HasSuffix(entry.Name(), ".auto.tfvars") || strings.HasSuffix(entry.Name(), ".auto.tfvars.json") {
+			autoFiles = append(autoFiles, filepath.Join(projectPath, entry.Name()))
+		}
+	}
+	sort.Strings(autoFiles)
+
+	return append(paths, autoFiles...)
+}
+
+// isAutoLoadedVarFile reports whether Terraform loads a root-level file without -var-file.
+func isAutoLoadedVarFile(name string) bool {
+	return name == "terraform.tfvars" || name == "terraform.tfvars.json" ||
+		strings.HasSuffix(name, ".auto.tfvars") || strings.HasSuffix(name, ".auto.tfvars.json")
+}
+
+// varFileFromPath builds a VarFile for a layer found by path rather than by discovery.
+func varFileFromPath(projectPath, path string, autoLoaded bool) VarFile {
+	relPath, err := filepath.Rel(projectPath, path)
+	if err != nil {
+		relPath = path
+	}
+	return VarFile{
+		Name:       filepath.Base(path),
+		Path:       relPath,
+		FullPath:   path,
+		AutoLoaded: autoLoaded,
+	}
+}
+
+// ResolveVarFileLayers returns the var files an environment is built from, in load order:
+//   - files Terraform always loads (terraform.tfvars, *.auto.tfvars), flagged AutoLoaded
+//   - one entry per layer rule, in rule order
+//
+// Rules are project-relative glob patterns where {env} is replaced by the environment
+// name (e.g. "common.tfvars", "variables/{env}/*.tfvars"); the rule "{env}" is the
+// environment's own var file, which is appended last if no rule placed it.
+// Rules that match nothing are skipped.
+func ResolveVarFileLayers(projectPath string, env VarFile, rules []string) []VarFile {
+	var layers []VarFile
+	seen := make(map[string]bool)
+	add := func(varFile VarFile) {
+		if !seen[varFile.FullPath] {
+			seen[varFile.FullPath] = true
+			layers = append(layers, varFile)
+		}
+	}
+
+	for _, path := range autoLoadedVarFiles(projectPath) {
+		add(varFileFromPath(projectPath, path, true))
+	}
+
+	for _, rule := range rules {
+		if rule == EnvLayer {
+			add(env)
+			continue
+		}
+		pattern := strings.ReplaceAll(rule, EnvLayer, env.EnvName)
+		matches, err := filepath.Glob(filepath.Join(projectPath, pattern))
+		if err != nil {
+			continue
+		}
+		sort.Strings(matches)
+		for _, match := range matches {
+			if info, err := os.Stat(match); err == nil && !info.IsDir() {
+				add(varFileFromPath(projectPath, match, false))
+			}
+		}
+	}
+
+	add(env)
+	return layers
+}
+
+// EnvironmentVarFiles filters discovered var files down to the ones that define an
+// environment: files matched by shared rules (rules without {env}, e.g. "common.tfvars")
+// are layers of every environment, not environments. Auto-loaded files (terraform.tfvars,
+// *.auto.tfvars) are layers of every environment too, unless the project has no other
+// var files: they are then the only environments there are.
+func EnvironmentVarFiles(projectPath string, varFiles []VarFile, rules []string) []VarFile {
+	shared := make(map[string]bool)
+	for _, rule := range rules {
+		if strings.Contains(rule, EnvLayer) {
+			continue
+		}
+		matches, _ := filepath.Glob(filepath.Join(projectPath, rule))
+		for _, match := range matches {
+			shared[filepath.Clean(match)] = true
+		}
+	}
+
+	var envFiles, autoLoaded []VarFile
+	for _, varFile := range varFiles {
+		switch {
+		case shared[filepath.Clean(varFile.FullPath)]:
+			continue
+		case varFile.AutoLoaded:
+			autoLoaded = append(autoLoaded, varFile)
+		default:
+			envFiles = append(envFiles, varFile)
+		}
+	}
+	if len(envFiles) == 0 {
+		return autoLoaded
+	}
+	return envFiles
+}
+
+// FormatVarFileLayers creates a numbered list of the effective var files.
+func FormatVarFileLayers(layers []VarFile) string {
+	if len(layers) == 0 {
+		return "No var files"
+	}
+
+	result := ""
+	for i, layer := range layers {
+		result += "  " + strconv.Itoa(i+1) + ". " + layer.Path
+		if layer.AutoLoaded {
+			result += " (always loaded)"
+		}
+		result += "\n"
+	}
+	return strings.TrimSuffix(result, "\n")
+}
@@ -0,0 +1,115 @@
+package terraform
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Nicolas-Rigaudy/lazytf/internal/config"
+)
+
+// layerPaths returns the project-relative paths of layers, auto-loaded ones marked with "*"
+func layerPaths(layers []VarFile) []string {
+	var paths []string
+	for _, layer := range layers {
+		path := filepath.ToSlash(layer.Path)
+		if layer.AutoLoaded {
+			path += "*"
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+func TestResolveVarFileLayers(t *testing.T) {
+	projectPath := t.TempDir()
+	for _, name := range []string{
+		"terraform.tfvars", "z.auto.tfvars", "a.auto.tfvars.json", "terraform.tfvars.json",
+		"common.tfvars", "variables/common.tfvars", "variables/dev.tfvars", "variables/prod.tfvars",
+		"variables/dev/network.tfvars", "variables/dev/app.tfvars", "nested/b.auto.tfvars",
+	} {
+		writeFile(t, projectPath, name, "", 0o644)
+	}
+	dev := VarFile{Name: "dev.tfvars", Path: "variables/dev.tfvars", FullPath: filepath.Join(projectPath, "variables", "dev.tfvars"), EnvName: "dev"}
+
+	// Terraform loads terraform.tfvars, terraform.tfvars.json, then *.auto.tfvars in lexical order
+	autoLoaded := []string{"terraform.tfvars*", "terraform.tfvars.json*", "a.auto.tfvars.json*", "z.auto.tfvars*"}
+
+	tests := []struct {
+		name  string
+		rules []string
+		want  []string
+	}{
+		{
+			name:  "default rules",
+			rules: config.DefaultVarFileLayers(),
+			want:  append(autoLoaded, "common.tfvars", "variables/common.tfvars", "variables/dev.tfvars"),
+		},
+		{
+			name:  "environment before a shared file",
+			rules: []string{"{env}", "common.tfvars"},
+			want:  append(autoLoaded, "variables/dev.tfvars", "common.tfvars"),
+		},
+		{
+			name:  "env glob in lexical order, own file appended last",
+			rules: []string{"variables/{env}/*.tfvars"},
+			want:  append(autoLoaded, "variables/dev/app.tfvars", "variables/dev/network.tfvars", "variables/dev.tfvars"),
+		},
+		{
+			name:  "missing and repeated files are skipped",
+			rules: []string{"missing.tfvars", "common.tfvars", "*.tfvars"},
+			want:  append(autoLoaded, "common.tfvars", "variables/dev.tfvars"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := layerPaths(ResolveVarFileLayers(projectPath, dev, tt.rules))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ResolveVarFileLayers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnvironmentVarFiles(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+		want  []string
+	}{
+		{
+			name:  "shared and auto-loaded files are layers",
+			files: []string{"common.tfvars", "terraform.tfvars", "shared.auto.tfvars", "variables/dev.tfvars", "variables/prod.tfvars"},
+			want:  []string{"variables/dev.tfvars", "variables/prod.tfvars"},
+		},
+		{
+			name:  "auto-loaded files only",
+			files: []string{"common.tfvars", "terraform.tfvars", "shared.auto.tfvars"},
+			want:  []string{"shared.auto.tfvars*", "terraform.tfvars*"},
+		},
+		{
+			name:  "terraform.tfvars below the root defines an environment",
+			files: []string{"terraform.tfvars", "envs/dev/terraform.tfvars"},
+			want:  []string{"envs/dev/terraform.tfvars"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectPath := t.TempDir()
+			for _, name := range tt.files {
+				writeFile(t, projectPath, name, "", 0o644)
+			}
+			varFiles, err := DiscoverVarFiles(projectPath, config.DefaultVarFileRoots(), config.DefaultConventions())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := layerPaths(EnvironmentVarFiles(projectPath, varFiles, config.DefaultVarFileLayers()))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EnvironmentVarFiles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@
 // and interacting with Terraform projects and workspaces.
 package terraform
 
+import "github.com/zclconf/go-cty/cty"
+
 // Project represents a Terraform project directory.
 type Project struct {
 	Name             string
@@ -9,6 +11,8 @@ type Project struct {
 	Workspaces       []string
 	CurrentWorkspace string
 	IsInitialized    bool
+	IsTerragrunt     bool // directory contains terragrunt.hcl, commands run through terragrunt
+	IsStack          bool // terragrunt.hcl is only included by the units below, commands always run-all
 }
 
 // VarFile represents a Terraform variables file (.tfvars).
@@ -17,14 +21,19 @@ type VarFile struct {
 	Path     string // relative path from project root
 	FullPath string // absolute path to the file
 	EnvName  string // extracted environment name (e.g., "dev2")
+	// true for terraform.tfvars and *.auto.tfvars in the project root:
+	// Terraform loads them on every run, they must not be passed as -var-file
+	AutoLoaded bool
 }
 
 // BackendVarFile represents a Terraform backend configuration file (.tfvars).
 type BackendVarFile struct {
-	Name     string // filename (e.g., "backend_dev2.tfvars")
-	Path     string // relative path from project root (e.g., "variables/backend/local")
-	FullPath string // absolute path to the file
-	EnvName  string // extracted environment name (e.g., "dev2")
+	Name     string               // filename (e.g., "backend_dev2.tfvars")
+	Path     string               // relative path from project root (e.g., "variables/backend/local")
+	FullPath string               // absolute path to the file
+	EnvName  string               // extracted environment name (e.g., "dev2")
+	Settings map[string]cty.Value // parsed contents (bucket, key, region, ...), nil if unparseable
+	ParseErr error                // why the contents couldn't be parsed
 }
 
 // BackendState represents the current Terraform backend initialization state.
@@ -34,6 +43,9 @@ type BackendState struct {
 	BackendConfig   map[string]string // key-value pairs from backend config
 	DetectedEnv     string            // environment name inferred from backend config (e.g., "dev2")
 	MatchedBackends []BackendVarFile  // the backend var files that matches current state
+	ExactMatch      bool              // MatchedBackends[0] is the backend file the project was initialized with
+	UnknownBackend  bool              // the initialized config matches none of the backend files
+	Declared        *DeclaredBackend  // backend block from the .tf files, nil if none is declared
 }
 
 // Mode represents how LazyTF is operating.
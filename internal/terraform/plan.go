@@ -0,0 +1,78 @@
+package terraform
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// SavedPlanFile is where plans made before an apply are saved, relative to the
+// working directory (the unit's cache directory with Terragrunt)
+const SavedPlanFile = ".terraform/lazytf.tfplan"
+
+type PlanOptions struct {
+	VarFiles []VarFile // layered var files, see ResolveVarFileLayers
+	Out      string    // save the plan to this file, "" to only display it
+	Input    bool
+}
+
+func RunPlan(ctx CommandContext, options PlanOptions) tea.Cmd {
+	args := []string{"plan"}
+
+	for _, varFile := range options.VarFiles {
+		// Terraform already loads these, passing them again would apply them twice
+		if varFile.AutoLoaded {
+			continue
+		}
+		args = append(args, fmt.Sprintf("-var-file=%s", varFile.FullPath))
+	}
+
+	if options.Out != "" {
+		args = append(args, "-out="+options.Out)
+	}
+
+	if !options.Input {
+		args = append(args, "-input=false")
+	}
+	return ctx.run(args)
+}
+
+// ShowPlan renders a saved plan as text (`show -no-color <planFile>`).
+func ShowPlan(ctx CommandContext, planFile string) (string, error) {
+	output, err := ctx.output([]string{"show", "-no-color", planFile})
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
+
+// planChangePattern matches the header of a resource change in plan output
+// e.g. "  # aws_instance.web will be updated in-place"
+var planChangePattern = regexp.MustCompile(`^\s*# ([^(\s].*?) (will be|must be) (.+)$`)
+
+// maxPlanSummaryChanges caps the resource changes listed by SummarizePlan
+const maxPlanSummaryChanges = 15
+
+// SummarizePlan extracts the resource changes and the totals line from plan output.
+// Example: ["aws_instance.web will be updated in-place", "Plan: 0 to add, 1 to change, 0 to destroy."]
+func SummarizePlan(plan string) []string {
+	var changes, totals []string
+	for _, line := range strings.Split(plan, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case planChangePattern.MatchString(line):
+			match := planChangePattern.FindStringSubmatch(line)
+			changes = append(changes, match[1]+" "+match[2]+" "+match[3])
+		case strings.HasPrefix(trimmed, "Plan: "), strings.HasPrefix(trimmed, "No changes."):
+			totals = append(totals, trimmed)
+		}
+	}
+
+	if len(changes) > maxPlanSummaryChanges {
+		more := len(changes) - maxPlanSummaryChanges
+		changes = append(changes[:maxPlanSummaryChanges], fmt.Sprintf("... and %d more", more))
+	}
+	return append(changes, totals...)
+}
@@ -0,0 +1,63 @@
+package terraform
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSummarizePlan(t *testing.T) {
+	plan := `
+Terraform used the selected providers to generate the following execution plan.
+
+Terraform will perform the following actions:
+
+  # aws_instance.web will be updated in-place
+  ~ resource "aws_instance" "web" {
+        # (12 unchanged attributes hidden)
+    }
+
+  # module.db.aws_db_instance.this["primary db"] must be replaced
+-/+ resource "aws_db_instance" "this" {
+      ~ engine_version = "15.3" -> "16.1" # forces replacement
+    }
+
+  # data.aws_ami.latest will be read during apply
+  # (depends on a resource or a module with changes pending)
+ <= data "aws_ami" "latest" {}
+
+Plan: 1 to add, 1 to change, 1 to destroy.
+`
+
+	want := []string{
+		"aws_instance.web will be updated in-place",
+		`module.db.aws_db_instance.this["primary db"] must be replaced`,
+		"data.aws_ami.latest will be read during apply",
+		"Plan: 1 to add, 1 to change, 1 to destroy.",
+	}
+	if got := SummarizePlan(plan); !reflect.DeepEqual(got, want) {
+		t.Errorf("SummarizePlan() = %q, want %q", got, want)
+	}
+
+	noChanges := "No changes. Your infrastructure matches the configuration.\n"
+	if got := SummarizePlan(noChanges); !reflect.DeepEqual(got, []string{strings.TrimSpace(noChanges)}) {
+		t.Errorf("SummarizePlan() = %q, want the no changes line", got)
+	}
+}
+
+func TestSummarizePlanCapsChanges(t *testing.T) {
+	var plan strings.Builder
+	for i := 0; i < maxPlanSummaryChanges+3; i++ {
+		fmt.Fprintf(&plan, "  # null_resource.n[%d] will be created\n", i)
+	}
+	plan.WriteString("Plan: 18 to add, 0 to change, 0 to destroy.\n")
+
+	got := SummarizePlan(plan.String())
+	if len(got) != maxPlanSummaryChanges+2 {
+		t.Fatalf("SummarizePlan() returned %d lines, want %d", len(got), maxPlanSummaryChanges+2)
+	}
+	if got[maxPlanSummaryChanges] != "... and 3 more" || !strings.HasPrefix(got[len(got)-1], "Plan: ") {
+		t.Errorf("SummarizePlan() ends with %q, want the remaining count and the totals", got[maxPlanSummaryChanges:])
+	}
+}
@@ -0,0 +1,62 @@
+// Package terraform provides functionality for detecting, discovering,
+// and interacting with Terraform projects and resources.
+//
+// This file contains provider detection:
+// - UsedProviders: List the providers a project's sources depend on
+package terraform
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// UsedProviders returns the local names of the providers a project uses (e.g. "aws", "google-beta"),
+// sorted. They are read from required_providers, provider blocks and the prefix of
+// resource and data source types, as Terraform infers providers from those too.
+func UsedProviders(projectPath string) []string {
+	found := make(map[string]bool)
+
+	files := parseProjectSources(projectPath)
+	for _, block := range terraformBlocks(files) {
+		for _, nested := range block.Body.Blocks {
+			if nested.Type != "required_providers" {
+				continue
+			}
+			for name := range nested.Body.Attributes {
+				found[name] = true
+			}
+		}
+	}
+	for _, block := range topLevelBlocks(files, "provider") {
+		if len(block.Labels) == 1 {
+			found[block.Labels[0]] = true
+		}
+	}
+	for _, blockType := range []string{"resource", "data"} {
+		for _, block := range topLevelBlocks(files, blockType) {
+			if len(block.Labels) == 0 {
+				continue
+			}
+			// provider = google-beta.west overrides the type prefix
+			if attr, explicit := block.Body.Attributes["provider"]; explicit {
+				if traversal, ok := attr.Expr.(*hclsyntax.ScopeTraversalExpr); ok {
+					found[traversal.Traversal.RootName()] = true
+				}
+				continue
+			}
+			// "aws_s3_bucket" belongs to "aws", "terraform_data" to the built-in provider
+			if prefix, _, ok := strings.Cut(block.Labels[0], "_"); ok && prefix != "terraform" {
+				found[prefix] = true
+			}
+		}
+	}
+
+	providers := make([]string, 0, len(found))
+	for name := range found {
+		providers = append(providers, name)
+	}
+	sort.Strings(providers)
+	return providers
+}
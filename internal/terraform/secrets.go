@@ -0,0 +1,281 @@
+// Package terraform provides functionality for detecting, discovering,
+// and interacting with Terraform projects and resources.
+//
+// This file contains secret detection for var and backend files:
+// - ScanValues: Flag literal secrets in parsed var file values
+// - ScanFiles: Scan var files and report findings with their file
+// - LooksLikeSecret: Check a single key and value (e.g. a -backend-config pair)
+// - SecretMasker: Hide detected secret values in text shown in the UI
+// - FormatSecretFindings: Format findings for UI display
+package terraform
+
+import (
+	"math"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// SecretMask replaces secret values wherever they are displayed.
+const SecretMask = "********"
+
+// SecretFinding is a literal value that looks like a secret.
+type SecretFinding struct {
+	File   string // var file path relative to the project (e.g., "variables/dev.tfvars")
+	Key    string // variable path (e.g., "db.password")
+	Reason string // why it was flagged (e.g., "AWS access key")
+	Value  string // the secret itself, only used for masking
+}
+
+// secretKeyPattern matches variable names that usually hold secrets.
+var secretKeyPattern = regexp.MustCompile(`(?i)(passw(or)?d|passwd|secret|token|api_?key|private_?key|access_?key|credential|conn(ection)?_?str)`)
+
+// nonSecretKeySuffix matches names that only describe a secret (e.g. "token_ttl", "secret_arn").
+var nonSecretKeySuffix = regexp.MustCompile(`(?i)_(ttl|name|arn|id)$`)
+
+// secretFormats are value formats of well-known credentials.
+var secretFormats = []struct {
+	name    string
+	pattern *regexp.Regexp
+}{
+	{"AWS access key", regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"private key", regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)},
+	{"GitHub token", regexp.MustCompile(`\b(ghp|gho|ghu|ghs|ghr)_[A-Za-z0-9]{36}\b|\bgithub_pat_[A-Za-z0-9_]{22,}`)},
+	{"Slack token", regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`)},
+	{"Google API key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{"Stripe key", regexp.MustCompile(`\b[sr]k_live_[0-9A-Za-z]{16,}`)},
+	{"JWT", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{8,}\.eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]+`)},
+}
+
+// secretReferencePrefixes mark values that point to a secret store instead of holding the secret.
+var secretReferencePrefixes = []string{"arn:", "ssm:", "vault:", "${", "data.", "projects/"}
+
+// ScanValues flags the string values of a var file that look like secrets,
+// descending into maps, objects and lists.
+func ScanValues(file string, values map[string]cty.Value) []SecretFinding {
+	var findings []SecretFinding
+	for _, name := range sortedKeys(values) {
+		findings = append(findings, scanValue(file, name, name, values[name])...)
+	}
+	return findings
+}
+
+// scanValue checks a single value; keyName is the closest map key, used for name heuristics.
+func scanValue(file, path, keyName string, value cty.Value) []SecretFinding {
+	if !value.IsKnown() || value.IsNull() {
+		return nil
+	}
+
+	ty := value.Type()
+	switch {
+	case ty == cty.String:
+		if reason := secretReason(keyName, value.AsString()); reason != "" {
+			return []SecretFinding{{File: file, Key: path, Reason: reason, Value: value.AsString()}}
+		}
+	case ty.IsMapType() || ty.IsObjectType():
+		var findings []SecretFinding
+		for it := value.ElementIterator(); it.Next(); {
+			key, element := it.Element()
+			findings = append(findings, scanValue(file, path+"."+key.AsString(), key.AsString(), element)...)
+		}
+		return findings
+	case ty.IsListType() || ty.IsTupleType() || ty.IsSetType():
+		var findings []SecretFinding
+		for it := value.ElementIterator(); it.Next(); {
+			index, element := it.Element()
+			elementPath := path + "[" + index.AsBigFloat().Text('f', -1) + "]"
+			if ty.IsSetType() {
+				elementPath = path + "[*]"
+			}
+			findings = append(findings, scanValue(file, elementPath, keyName, element)...)
+		}
+		return findings
+	}
+	return nil
+}
+
+// LooksLikeSecret reports whether a value set for the given key looks like a secret.
+func LooksLikeSecret(keyName, value string) bool {
+	return secretReason(keyName, value) != ""
+}
+
+// secretReason returns why a value looks like a secret, or "" if it doesn't.
+func secretReason(keyName, value string) string {
+	for _, format := range secretFormats {
+		if format.pattern.MatchString(value) {
+			return format.name
+		}
+	}
+
+	if len(value) < 4 || strings.ContainsAny(value, " \n") {
+		return ""
+	}
+	for _, prefix := range secretReferencePrefixes {
+		if strings.HasPrefix(value, prefix) {
+			return ""
+		}
+	}
+
+	if secretKeyPattern.MatchString(keyName) && !nonSecretKeySuffix.MatchString(keyName) {
+		return "secret-like name"
+	}
+	if len(value) >= 20 && shannonEntropy(value) >= 4.0 && hasMixedCharacters(value) {
+		return "high-entropy value"
+	}
+	return ""
+}
+
+// shannonEntropy returns the entropy of a string in bits per character.
+func shannonEntropy(value string) float64 {
+	counts := make(map[rune]int)
+	total := 0
+	for _, r := range value {
+		counts[r]++
+		total++
+	}
+
+	entropy := 0.0
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// hasMixedCharacters reports whether a value mixes letters and digits, as random keys do
+// (long identifiers like "my-application-name" don't).
+func hasMixedCharacters(value string) bool {
+	hasLetter, hasDigit := false, false
+	for _, r := range value {
+		switch {
+		case r >= '0' && r <= '9':
+			hasDigit = true
+		case (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z'):
+			hasLetter = true
+		}
+	}
+	return hasLetter && hasDigit
+}
+
+// ScanFiles scans var or backend files (full paths) for secrets.
+// File names in findings are relative to projectPath. Unparseable files are skipped.
+func ScanFiles(projectPath string, paths []string) []SecretFinding {
+	var findings []SecretFinding
+	seen := make(map[string]bool)
+
+	for _, path := range paths {
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+
+		values, err := ParseVarFile(path)
+		if err != nil {
+			continue
+		}
+		relPath, err := filepath.Rel(projectPath, path)
+		if err != nil {
+			relPath = path
+		}
+		findings = append(findings, ScanValues(relPath, values)...)
+	}
+	return findings
+}
+
+// SecretMasker hides secret values in text.
+type SecretMasker struct {
+	values      []string         // longest first, so a secret containing another is masked whole
+	assignments []*regexp.Regexp // short values, masked only where assigned to their key
+}
+
+// NewSecretMasker creates a masker for the values of the given findings.
+// Values flagged only by their variable name that are short or low-entropy
+// (e.g. admin_password = "changeme") are common words or numbers, so they are masked
+// only in "key = value" and "key: value" contexts, not everywhere.
+func NewSecretMasker(findings []SecretFinding) SecretMasker {
+	seen := make(map[string]bool)
+	var masker SecretMasker
+
+	for _, finding := range findings {
+		// Quoted forms cover values displayed in HCL syntax (escaped newlines and quotes)
+		quoted := strings.Trim(strconv.Quote(finding.Value), `"`)
+		values := []string{finding.Value}
+		if quoted != finding.Value {
+			values = append(values, quoted)
+		}
+
+		if finding.Reason == "secret-like name" && !isDistinctiveValue(finding.Value) {
+			key := findingKeyName(finding.Key)
+			if seen[key+"="+finding.Value] {
+				continue
+			}
+			seen[key+"="+finding.Value] = true
+			for _, value := range values {
+				masker.assignments = append(masker.assignments, assignmentPattern(key, value))
+			}
+			continue
+		}
+
+		for _, value := range values {
+			if len(value) >= 4 && !seen[value] {
+				seen[value] = true
+				masker.values = append(masker.values, value)
+			}
+		}
+	}
+
+	sort.Slice(masker.values, func(i, j int) bool { return len(masker.values[i]) > len(masker.values[j]) })
+	return masker
+}
+
+// isDistinctiveValue reports whether a value is unlikely to appear in text by chance:
+// long, or random-looking.
+func isDistinctiveValue(value string) bool {
+	return len(value) >= 16 || (len(value) >= 8 && shannonEntropy(value) >= 3.0 && hasMixedCharacters(value))
+}
+
+// findingKeyName returns the variable name a finding was flagged for
+// (e.g. "db.password" -> "password", "tokens[0]" -> "tokens").
+func findingKeyName(path string) string {
+	if i := strings.LastIndex(path, "."); i >= 0 {
+		path = path[i+1:]
+	}
+	if i := strings.Index(path, "["); i >= 0 {
+		path = path[:i]
+	}
+	return path
+}
+
+// assignmentPattern matches value assigned to key, quoted or not, in HCL, YAML or JSON syntax.
+// The key prefix and the character following the value are captured so they are kept.
+func assignmentPattern(key, value string) *regexp.Regexp {
+	return regexp.MustCompile(`(?m)(\b` + regexp.QuoteMeta(key) + `"?\s*[=:]\s*"?)` + regexp.QuoteMeta(value) + `("|\s|,|$)`)
+}
+
+// Mask replaces every secret value in text with SecretMask.
+func (m SecretMasker) Mask(text string) string {
+	for _, value := range m.values {
+		text = strings.ReplaceAll(text, value, SecretMask)
+	}
+	for _, pattern := range m.assignments {
+		text = pattern.ReplaceAllString(text, "${1}"+SecretMask+"${2}")
+	}
+	return text
+}
+
+// FormatSecretFindings creates a human-readable list of findings, without the values.
+func FormatSecretFindings(findings []SecretFinding) string {
+	if len(findings) == 0 {
+		return "✅ No literal secrets found"
+	}
+
+	result := "🔑 Literal secrets found (values are masked):\n"
+	for _, finding := range findings {
+		result += "  • " + finding.Key + " in " + finding.File + " (" + finding.Reason + ")\n"
+	}
+	return strings.TrimSuffix(result, "\n")
+}
@@ -0,0 +1,112 @@
+// Package terraform provides functionality for detecting, discovering,
+// and interacting with Terraform projects and resources.
+//
+// This file contains helpers for reading a project's .tf/.tofu sources:
+// - parseProjectSources: Parse every .tf/.tofu file in the project root
+// - terraformBlocks: Collect the top-level terraform {} blocks
+// - sourceFile: Find the file a block was read from
+// - stringAttribute: Read a literal string attribute from a block body
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// parseProjectSources parses all .tf and .tofu files in the project root (not recursive,
+// Terraform only loads the root module directory).
+// Like OpenTofu, a foo.tf file is skipped when foo.tofu exists next to it.
+// Files that fail to parse are skipped so one broken file doesn't hide the others.
+func parseProjectSources(projectPath string) []*hcl.File {
+	entries, err := os.ReadDir(projectPath)
+	if err != nil {
+		return nil
+	}
+
+	tofuFiles := make(map[string]bool)
+	for _, entry := range entries {
+		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".tofu" {
+			tofuFiles[strings.TrimSuffix(entry.Name(), ".tofu")] = true
+		}
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		switch filepath.Ext(entry.Name()) {
+		case ".tofu":
+			names = append(names, entry.Name())
+		case ".tf":
+			if !tofuFiles[strings.TrimSuffix(entry.Name(), ".tf")] {
+				names = append(names, entry.Name())
+			}
+		}
+	}
+	sort.Strings(names)
+
+	parser := hclparse.NewParser()
+	var files []*hcl.File
+	for _, name := range names {
+		file, diags := parser.ParseHCLFile(filepath.Join(projectPath, name))
+		if diags.HasErrors() || file == nil {
+			continue
+		}
+		files = append(files, file)
+	}
+	return files
+}
+
+// terraformBlocks returns the top-level `terraform {}` blocks of the parsed files.
+func terraformBlocks(files []*hcl.File) []*hclsyntax.Block {
+	return topLevelBlocks(files, "terraform")
+}
+
+// topLevelBlocks returns all top-level blocks of the given type.
+func topLevelBlocks(files []*hcl.File, blockType string) []*hclsyntax.Block {
+	var blocks []*hclsyntax.Block
+	for _, file := range files {
+		body, ok := file.Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+		for _, block := range body.Blocks {
+			if block.Type == blockType {
+				blocks = append(blocks, block)
+			}
+		}
+	}
+	return blocks
+}
+
+// sourceFile returns the parsed file a block was read from.
+func sourceFile(files []*hcl.File, block *hclsyntax.Block) *hcl.File {
+	for _, file := range files {
+		if file.Body.MissingItemRange().Filename == block.Range().Filename {
+			return file
+		}
+	}
+	return nil
+}
+
+// stringAttribute returns the value of a literal string attribute in a block body.
+// Returns "" if the attribute is missing or can't be evaluated without context.
+func stringAttribute(body *hclsyntax.Body, name string) string {
+	attr, ok := body.Attributes[name]
+	if !ok {
+		return ""
+	}
+	value, diags := attr.Expr.Value(nil)
+	if diags.HasErrors() || value.IsNull() || !value.IsKnown() || value.Type() != cty.String {
+		return ""
+	}
+	return value.AsString()
+}
@@ -3,7 +3,8 @@
 //
 // This file contains Terraform state detection logic:
 // - DetectCurrentBackend: Read .terraform/terraform.tfstate to detect current backend
-// - inferEnvFromBackendConfig: Extract environment name from backend config
+// - matchBackendConfig: Find the backend file the initialized config was built from
+// - inferEnvFromBackendConfig: Extract environment name from backend config (fallback)
 // - FormatBackendState: Format backend state for UI display
 package terraform
 
@@ -11,15 +12,18 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 )
 
 // DetectCurrentBackend checks if Terraform is initialized and detects the current backend configuration.
-// It reads .terraform/terraform.tfstate to determine which backend is currently active.
+// It reads .terraform/terraform.tfstate to determine which backend is currently active,
+// then looks for the backend file the config exactly matches before guessing the env.
 func DetectCurrentBackend(projectPath string, backendVarFiles []BackendVarFile) BackendState {
 	state := BackendState{
 		IsInitialized: false,
 		BackendConfig: make(map[string]string),
+		Declared:      ParseDeclaredBackend(projectPath),
 	}
 
 	// Check if .terraform directory exists
@@ -58,12 +62,29 @@ func DetectCurrentBackend(projectPath string, backendVarFiles []BackendVarFile)
 
 	// Convert config to string map for easier handling
 	for key, value := range tfstate.Backend.Config {
-		if strValue, ok := value.(string); ok {
-			state.BackendConfig[key] = strValue
+		switch v := value.(type) {
+		case string:
+			state.BackendConfig[key] = v
+		case bool:
+			state.BackendConfig[key] = strconv.FormatBool(v)
+		case float64:
+			state.BackendConfig[key] = strconv.FormatFloat(v, 'f', -1, 64)
 		}
 	}
 
-	// Try to infer environment name from backend config
+	// The backend file whose settings the initialized config was built from
+	if match := matchBackendConfig(state.BackendConfig, state.Declared, backendVarFiles); match != nil {
+		state.ExactMatch = true
+		state.MatchedBackends = []BackendVarFile{*match}
+		state.DetectedEnv = match.EnvName
+		if match.EnvName != "" {
+			return state
+		}
+	} else if len(state.BackendConfig) > 0 && hasBackendSettings(backendVarFiles) {
+		state.UnknownBackend = true
+	}
+
+	// Fall back to inferring the environment name from backend config
 	// Common patterns:
 	// - S3 key: "dev2/terraform.tfstate" or "terraform.tfstate.d/dev2"
 	// - File path: "terraform-dev2.tfstate"
@@ -71,12 +92,68 @@ func DetectCurrentBackend(projectPath string, backendVarFiles []BackendVarFile)
 
 	// Try to match with available backend var files
 	if state.DetectedEnv != "" {
-		state.MatchedBackends = MatchBackendsForEnv(state.DetectedEnv, backendVarFiles)
+		if state.ExactMatch {
+			// The matched file is a generic one, it says nothing about the environment
+			state.ExactMatch = false
+		} else {
+			state.MatchedBackends = MatchBackendsForEnv(state.DetectedEnv, backendVarFiles)
+		}
 	}
 
 	return state
 }
 
+// matchBackendConfig returns the backend file that, combined with the backend block's own
+// settings, produces exactly the initialized config: both must set the same keys, with the
+// same values, so a setting added or changed at init time (e.g. -backend-config) breaks the match.
+// When several files match, the one setting the most keys wins (a specific file over a
+// generic one). Returns nil when no file matches.
+func matchBackendConfig(config map[string]string, declared *DeclaredBackend, backendVarFiles []BackendVarFile) *BackendVarFile {
+	var best *BackendVarFile
+	bestKeys := 0
+
+	for i := range backendVarFiles {
+		settings := backendVarFiles[i].StringSettings()
+		if len(settings) == 0 {
+			continue
+		}
+
+		effective := make(map[string]string)
+		if declared != nil {
+			for key, value := range declared.Settings {
+				effective[key] = value
+			}
+		}
+		for key, value := range settings {
+			effective[key] = value
+		}
+
+		matches := len(effective) == len(config)
+		for key, value := range effective {
+			if configValue, ok := config[key]; !ok || configValue != value {
+				matches = false
+				break
+			}
+		}
+		if matches && len(settings) > bestKeys {
+			best = &backendVarFiles[i]
+			bestKeys = len(settings)
+		}
+	}
+
+	return best
+}
+
+// hasBackendSettings reports whether any backend file has parsed settings to compare with.
+func hasBackendSettings(backendVarFiles []BackendVarFile) bool {
+	for _, backend := range backendVarFiles {
+		if len(backend.StringSettings()) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // inferEnvFromBackendConfig tries to extract environment name from backend configuration.
 // Examples:
 //   - S3 key "dev2/terraform.tfstate" -> "dev2"
@@ -131,7 +208,11 @@ func inferEnvFromBackendConfig(config map[string]string) string {
 // Shows initialization status, backend type, and which environment is currently active.
 func FormatBackendState(state BackendState) string {
 	if !state.IsInitialized {
-		return "❌ Not initialized\n\nRun 'terraform init' with a backend config to get started."
+		result := "❌ Not initialized\n\n"
+		if state.Declared != nil {
+			result += "Declared Backend: " + state.Declared.Type + " (" + state.Declared.File + ")\n\n"
+		}
+		return result + "Run 'terraform init' with a backend config to get started."
 	}
 
 	result := "✅ Initialized\n\n"
@@ -140,11 +221,25 @@ func FormatBackendState(state BackendState) string {
 		result += "Backend Type: " + state.BackendType + "\n"
 	}
 
+	if warning := BackendTypeMismatch(state); warning != "" {
+		result += "⚠️  " + warning + "\n"
+	}
+
+	if state.UnknownBackend {
+		result += "⚠️  Unknown/modified backend: the initialized config matches no backend file\n"
+	}
+
 	if state.DetectedEnv != "" {
-		result += "Current Environment: " + state.DetectedEnv + "\n"
+		if state.ExactMatch {
+			result += "Current Environment: " + state.DetectedEnv + "\n"
+		} else {
+			result += "Current Environment (guessed): " + state.DetectedEnv + "\n"
+		}
 	}
 
-	if len(state.MatchedBackends) > 0 {
+	if state.ExactMatch {
+		result += "\nInitialized with: " + FormatBackendInfo(state.MatchedBackends)
+	} else if len(state.MatchedBackends) > 0 {
 		result += "\n" + FormatBackendInfo(state.MatchedBackends)
 	}
 
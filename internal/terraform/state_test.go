@@ -0,0 +1,124 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// backendFile builds a parsed backend file with string settings.
+func backendFile(name, envName string, settings map[string]string) BackendVarFile {
+	values := make(map[string]cty.Value, len(settings))
+	for key, value := range settings {
+		values[key] = cty.StringVal(value)
+	}
+	return BackendVarFile{Name: name, EnvName: envName, Settings: values}
+}
+
+func TestMatchBackendConfig(t *testing.T) {
+	dev := backendFile("backend_dev.tfvars", "dev", map[string]string{"bucket": "states", "key": "dev/terraform.tfstate"})
+	prod := backendFile("backend_prod.tfvars", "prod", map[string]string{"bucket": "states", "key": "prod/terraform.tfstate"})
+	generic := backendFile("backend.tfvars", "", map[string]string{"bucket": "states"})
+	unparsed := BackendVarFile{Name: "broken.tfvars", EnvName: "broken"}
+	declared := &DeclaredBackend{Type: "s3", Settings: map[string]string{"region": "eu-west-1"}}
+
+	tests := []struct {
+		name     string
+		config   map[string]string
+		declared *DeclaredBackend
+		files    []BackendVarFile
+		want     string // name of the matched file, "" for none
+	}{
+		{
+			name:     "exact match with the block settings",
+			config:   map[string]string{"bucket": "states", "key": "prod/terraform.tfstate", "region": "eu-west-1"},
+			declared: declared,
+			files:    []BackendVarFile{dev, prod, unparsed},
+			want:     "backend_prod.tfvars",
+		},
+		{
+			name:   "specific file wins over a generic one",
+			config: map[string]string{"bucket": "states", "key": "dev/terraform.tfstate"},
+			files:  []BackendVarFile{generic, dev},
+			want:   "backend_dev.tfvars",
+		},
+		{
+			name:   "changed value",
+			config: map[string]string{"bucket": "states", "key": "staging/terraform.tfstate"},
+			files:  []BackendVarFile{dev, prod},
+		},
+		{
+			name:   "extra key set at init time",
+			config: map[string]string{"bucket": "states", "key": "dev/terraform.tfstate", "encrypt": "true"},
+			files:  []BackendVarFile{dev},
+		},
+		{
+			name:   "key missing from the initialized config",
+			config: map[string]string{"bucket": "states"},
+			files:  []BackendVarFile{dev},
+		},
+		{
+			name:   "generic file alone",
+			config: map[string]string{"bucket": "states"},
+			files:  []BackendVarFile{generic, dev},
+			want:   "backend.tfvars",
+		},
+		{
+			name:   "no parsed files",
+			config: map[string]string{"bucket": "states"},
+			files:  []BackendVarFile{unparsed},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ""
+			if match := matchBackendConfig(tt.config, tt.declared, tt.files); match != nil {
+				got = match.Name
+			}
+			if got != tt.want {
+				t.Errorf("matchBackendConfig() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectCurrentBackend(t *testing.T) {
+	generic := backendFile("backend.tfvars", "", map[string]string{"bucket": "states", "key": "dev/terraform.tfstate"})
+	dev := backendFile("backend_dev.tfvars", "dev", map[string]string{"bucket": "states", "key": "dev/terraform.tfstate"})
+	changed := backendFile("backend_dev.tfvars", "dev", map[string]string{"bucket": "states", "key": "dev/terraform.tfstate", "region": "eu-west-1"})
+
+	tests := []struct {
+		name        string
+		files       []BackendVarFile
+		wantEnv     string
+		wantExact   bool
+		wantUnknown bool
+	}{
+		{name: "env file matches", files: []BackendVarFile{dev}, wantEnv: "dev", wantExact: true},
+		{name: "generic file matches, env is guessed", files: []BackendVarFile{generic}, wantEnv: "dev"},
+		{name: "no file matches", files: []BackendVarFile{changed}, wantEnv: "dev", wantUnknown: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectPath := t.TempDir()
+			if err := os.MkdirAll(filepath.Join(projectPath, ".terraform"), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			// Terraform stores unset settings as null
+			tfstate := `{"backend": {"type": "s3", "config": {"bucket": "states", "key": "dev/terraform.tfstate", "region": null}}}`
+			if err := os.WriteFile(filepath.Join(projectPath, ".terraform", "terraform.tfstate"), []byte(tfstate), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			state := DetectCurrentBackend(projectPath, tt.files)
+			if state.DetectedEnv != tt.wantEnv || state.ExactMatch != tt.wantExact || state.UnknownBackend != tt.wantUnknown {
+				t.Errorf("DetectCurrentBackend() env=%q exact=%v unknown=%v, want env=%q exact=%v unknown=%v",
+					state.DetectedEnv, state.ExactMatch, state.UnknownBackend, tt.wantEnv, tt.wantExact, tt.wantUnknown)
+			}
+		})
+	}
+}
@@ -0,0 +1,274 @@
+// Package terraform provides functionality for detecting, discovering,
+// and interacting with Terraform projects and resources.
+//
+// This file contains Terragrunt unit detection and parsing logic:
+// - IsTerragruntProject: Check if a directory contains a terragrunt.hcl
+// - IsTerragruntUnit: Check if a terragrunt.hcl deploys a module rather than only being included
+// - ParseTerragruntConfig: Read source, include and dependency blocks from terragrunt.hcl
+// - DiscoverTerragruntUnits: List the units below a directory (for run-all)
+// - FormatTerragruntConfig: Format the include/dependency structure for UI display
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// TerragruntConfigFile is the file that marks a directory as a Terragrunt unit.
+const TerragruntConfigFile = "terragrunt.hcl"
+
+// TerragruntInclude is an `include "name" { path = ... }` block.
+type TerragruntInclude struct {
+	Name string // block label, "" for the legacy unlabeled form
+	Path string // literal path, or the expression source (e.g. "find_in_parent_folders()")
+}
+
+// TerragruntDependency is a `dependency "name" { config_path = ... }` block.
+type TerragruntDependency struct {
+	Name       string
+	ConfigPath string
+}
+
+// TerragruntConfig holds the parts of a terragrunt.hcl that describe the unit's structure.
+type TerragruntConfig struct {
+	Source       string                 // terraform { source = ... }
+	Includes     []TerragruntInclude    // include blocks
+	Dependencies []TerragruntDependency // dependency blocks (outputs are read from these)
+	DependsOn    []string               // dependencies { paths = [...] } (ordering only)
+}
+
+// IsTerragruntProject checks if a directory is a Terragrunt unit (contains terragrunt.hcl).
+func IsTerragruntProject(path string) bool {
+	info, err := os.Stat(filepath.Join(path, TerragruntConfigFile))
+	return err == nil && !info.IsDir()
+}
+
+// IsTerragruntUnit checks if a directory's terragrunt.hcl deploys something: it has a
+// terraform { source }, an include block (the source usually comes from the included
+// config, as in the _envcommon pattern), sits next to .tf files or has no terragrunt.hcl
+// below it. A root terragrunt.hcl that only holds settings for the units below it
+// (remote_state, inputs) is not a unit.
+func IsTerragruntUnit(path string) bool {
+	if !IsTerragruntProject(path) {
+		return false
+	}
+	return deploysTerragruntModule(path) || !hasTerragruntConfigBelow(path)
+}
+
+// deploysTerragruntModule checks the signs of a unit found in the directory itself:
+// .tf files, a terraform { source } or an include block.
+// Configs that fail to parse are treated as units so they still show up.
+func deploysTerragruntModule(path string) bool {
+	if isTF, _ := IsTerraformProject(path); isTF {
+		return true
+	}
+	cfg, err := ParseTerragruntConfig(path)
+	return err != nil || cfg.Source != "" || len(cfg.Includes) > 0
+}
+
+// hasTerragruntConfigBelow checks if any subdirectory of path contains a terragrunt.hcl.
+func hasTerragruntConfigBelow(path string) bool {
+	found := false
+	filepath.Walk(path, func(dir string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() {
+			return nil
+		}
+		if isTerragruntCache(info.Name()) {
+			return filepath.SkipDir
+		}
+		if dir != path && IsTerragruntProject(dir) {
+			found = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return found
+}
+
+// isTerragruntCache reports whether a directory holds module copies made by terragrunt or terraform.
+func isTerragruntCache(name string) bool {
+	return name == ".terragrunt-cache" || name == ".terraform"
+}
+
+// markConfigAncestors records every directory above path as having a terragrunt.hcl below it.
+func markConfigAncestors(configBelow map[string]bool, path string) {
+	for dir := filepath.Dir(path); !configBelow[dir]; dir = filepath.Dir(dir) {
+		configBelow[dir] = true
+		if filepath.Dir(dir) == dir {
+			return
+		}
+	}
+}
+
+// ParseTerragruntConfig reads the include/dependency structure of a unit's terragrunt.hcl.
+// Values that need terragrunt functions to evaluate are kept as their source expression.
+func ParseTerragruntConfig(unitPath string) (TerragruntConfig, error) {
+	var cfg TerragruntConfig
+
+	file, diags := hclparse.NewParser().ParseHCLFile(filepath.Join(unitPath, TerragruntConfigFile))
+	if diags.HasErrors() {
+		return cfg, diags
+	}
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return cfg, nil
+	}
+
+	for _, block := range body.Blocks {
+		switch block.Type {
+		case "terraform":
+			cfg.Source = expressionText(file, block.Body, "source")
+		case "include":
+			cfg.Includes = append(cfg.Includes, TerragruntInclude{
+				Name: firstLabel(block),
+				Path: expressionText(file, block.Body, "path"),
+			})
+		case "dependency":
+			cfg.Dependencies = append(cfg.Dependencies, TerragruntDependency{
+				Name:       firstLabel(block),
+				ConfigPath: expressionText(file, block.Body, "config_path"),
+			})
+		case "dependencies":
+			cfg.DependsOn = append(cfg.DependsOn, stringListAttribute(file, block.Body, "paths")...)
+		}
+	}
+
+	return cfg, nil
+}
+
+// firstLabel returns the first label of a block, or "" if it has none.
+func firstLabel(block *hclsyntax.Block) string {
+	if len(block.Labels) == 0 {
+		return ""
+	}
+	return block.Labels[0]
+}
+
+// expressionText returns an attribute as a string: its value if it's a literal string,
+// otherwise the expression as written in the file.
+func expressionText(file *hcl.File, body *hclsyntax.Body, name string) string {
+	attr, ok := body.Attributes[name]
+	if !ok {
+		return ""
+	}
+	if value := stringAttribute(body, name); value != "" {
+		return value
+	}
+	return strings.TrimSpace(string(attr.Expr.Range().SliceBytes(file.Bytes)))
+}
+
+// stringListAttribute returns the elements of a list attribute, each as a literal
+// string when possible or as its source expression otherwise.
+func stringListAttribute(file *hcl.File, body *hclsyntax.Body, name string) []string {
+	attr, ok := body.Attributes[name]
+	if !ok {
+		return nil
+	}
+
+	tuple, ok := attr.Expr.(*hclsyntax.TupleConsExpr)
+	if !ok {
+		return []string{strings.TrimSpace(string(attr.Expr.Range().SliceBytes(file.Bytes)))}
+	}
+
+	var items []string
+	for _, expr := range tuple.Exprs {
+		value, diags := expr.Value(nil)
+		if !diags.HasErrors() && value.IsKnown() && !value.IsNull() && value.Type() == cty.String {
+			items = append(items, value.AsString())
+			continue
+		}
+		items = append(items, strings.TrimSpace(string(expr.Range().SliceBytes(file.Bytes))))
+	}
+	return items
+}
+
+// DiscoverTerragruntUnits lists the Terragrunt units at or below stackPath (see IsTerragruntUnit),
+// as paths relative to stackPath ("." for stackPath itself).
+// These are the units a `terragrunt run-all` in stackPath operates on.
+func DiscoverTerragruntUnits(stackPath string) []string {
+	var configs []string
+	configBelow := make(map[string]bool)
+
+	filepath.Walk(stackPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if isTerragruntCache(info.Name()) {
+			return filepath.SkipDir
+		}
+		if IsTerragruntProject(path) {
+			configs = append(configs, path)
+			markConfigAncestors(configBelow, path)
+		}
+		return nil
+	})
+
+	var units []string
+	for _, path := range configs {
+		if configBelow[path] && !deploysTerragruntModule(path) {
+			continue
+		}
+		rel, err := filepath.Rel(stackPath, path)
+		if err != nil {
+			rel = path
+		}
+		units = append(units, rel)
+	}
+	return units
+}
+
+// FormatTerragruntConfig creates a human-readable description of a unit's structure.
+func FormatTerragruntConfig(cfg TerragruntConfig, units []string) string {
+	result := ""
+
+	if cfg.Source != "" {
+		result += "Source: " + cfg.Source + "\n"
+	}
+
+	if len(cfg.Includes) > 0 {
+		result += "Includes:\n"
+		for _, include := range cfg.Includes {
+			name := include.Name
+			if name == "" {
+				name = "(unnamed)"
+			}
+			result += "  • " + name + " → " + include.Path + "\n"
+		}
+	}
+
+	if len(cfg.Dependencies) > 0 {
+		result += "Dependencies:\n"
+		for _, dep := range cfg.Dependencies {
+			result += "  • " + dep.Name + " → " + dep.ConfigPath + "\n"
+		}
+	}
+
+	if len(cfg.DependsOn) > 0 {
+		result += "Runs after:\n"
+		for _, path := range cfg.DependsOn {
+			result += "  • " + path + "\n"
+		}
+	}
+
+	// Only worth listing when run-all would reach more than this unit
+	if len(units) > 1 {
+		result += "Units (run-all):\n"
+		for _, unit := range units {
+			result += "  • " + unit + "\n"
+		}
+	}
+
+	if result == "" {
+		return "No include or dependency blocks\n"
+	}
+	return result
+}
@@ -0,0 +1,104 @@
+package terraform
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Nicolas-Rigaudy/lazytf/internal/config"
+)
+
+// writeTerragruntLive creates a live repository using the _envcommon pattern:
+// a root config included by every unit, units getting their source from _envcommon
+func writeTerragruntLive(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	files := map[string]string{
+		"terragrunt.hcl":                             `remote_state { backend = "s3" }`,
+		"_envcommon/vpc.hcl":                         `terraform { source = "git::https://example.com/modules.git//vpc" }`,
+		"prod/vpc/terragrunt.hcl":                    "include \"root\" {\n  path = find_in_parent_folders()\n}\ninclude \"envcommon\" {\n  path = \"${dirname(find_in_parent_folders())}/_envcommon/vpc.hcl\"\n}\n",
+		"prod/app/terragrunt.hcl":                    "terraform {\n  source = \"../../modules/app\"\n}\ndependency \"vpc\" {\n  config_path = \"../vpc\"\n}\n",
+		"prod/db/terragrunt.hcl":                     `inputs = { size = "large" }`,
+		"prod/db/.terragrunt-cache/x/terragrunt.hcl": `inputs = {}`,
+		"prod/local/terragrunt.hcl":                  `inputs = {}`,
+		"prod/local/main.tf":                         "",
+		"prod/local/nested/terragrunt.hcl":           `terraform { source = "../module" }`,
+		"modules/app/main.tf":                        "",
+		"broken/terragrunt.hcl":                      "terraform {",
+		"broken/child/terragrunt.hcl":                `terraform { source = "x" }`,
+	}
+	for name, content := range files {
+		writeFile(t, root, name, content, 0o644)
+	}
+	return root
+}
+
+func TestIsTerragruntUnit(t *testing.T) {
+	root := writeTerragruntLive(t)
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: ".", want: false},           // root config included by the units below
+		{path: "prod", want: false},        // no terragrunt.hcl
+		{path: "prod/vpc", want: true},     // source inherited through include
+		{path: "prod/app", want: true},     // own source
+		{path: "prod/db", want: true},      // leaf, the cache below doesn't count
+		{path: "prod/local", want: true},   // next to .tf files
+		{path: "broken", want: true},       // parse errors still show up
+		{path: "modules/app", want: false}, // plain Terraform module
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := IsTerragruntUnit(filepath.Join(root, tt.path)); got != tt.want {
+				t.Errorf("IsTerragruntUnit(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiscoverTerragruntUnits(t *testing.T) {
+	root := writeTerragruntLive(t)
+
+	want := []string{"broken", "broken/child", "prod/app", "prod/db", "prod/local", "prod/local/nested", "prod/vpc"}
+	if got := DiscoverTerragruntUnits(root); !reflect.DeepEqual(got, want) {
+		t.Errorf("DiscoverTerragruntUnits() = %v, want %v", got, want)
+	}
+	if got := DiscoverTerragruntUnits(filepath.Join(root, "prod", "vpc")); !reflect.DeepEqual(got, []string{"."}) {
+		t.Errorf("DiscoverTerragruntUnits() of a unit = %v, want [.]", got)
+	}
+}
+
+func TestDiscoverProjectsTerragrunt(t *testing.T) {
+	root := writeTerragruntLive(t)
+
+	projects, err := DiscoverProjects(config.Config{SearchPaths: []string{root}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type found struct {
+		Path       string
+		Terragrunt bool
+		Stack      bool
+	}
+	var got []found
+	for _, project := range projects {
+		rel, _ := filepath.Rel(root, project.Path)
+		got = append(got, found{Path: rel, Terragrunt: project.IsTerragrunt, Stack: project.IsStack})
+	}
+	want := []found{
+		{Path: ".", Terragrunt: true, Stack: true},
+		{Path: "broken", Terragrunt: true},
+		{Path: "modules/app"},
+		{Path: "prod/app", Terragrunt: true},
+		{Path: "prod/db", Terragrunt: true},
+		{Path: "prod/local", Terragrunt: true},
+		{Path: "prod/vpc", Terragrunt: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiscoverProjects() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,143 @@
+// Package terraform provides functionality for detecting, discovering,
+// and interacting with Terraform projects and resources.
+//
+// This file contains var file parsing logic:
+// - ParseVarFile: Parse a .tfvars (HCL) or .tfvars.json file into typed values
+// - FormatValue: Render a single value in HCL syntax
+// - FormatVarValues: Render all values of a var file for UI display
+package terraform
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ParseVarFile parses a var file into typed values keyed by variable name.
+// Files ending in .json are parsed as JSON, everything else as HCL.
+// Var files may only contain literal values, so no evaluation context is needed.
+func ParseVarFile(path string) (map[string]cty.Value, error) {
+	parser := hclparse.NewParser()
+
+	var file *hcl.File
+	var diags hcl.Diagnostics
+	if strings.HasSuffix(path, ".json") {
+		file, diags = parser.ParseJSONFile(path)
+	} else {
+		file, diags = parser.ParseHCLFile(path)
+	}
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	attrs, diags := file.Body.JustAttributes()
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	values := make(map[string]cty.Value, len(attrs))
+	for name, attr := range attrs {
+		value, diags := attr.Expr.Value(nil)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		values[name] = value
+	}
+	return values, nil
+}
+
+// sortedKeys returns the keys of a value map in alphabetical order.
+func sortedKeys(values map[string]cty.Value) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// FormatVarValues renders var file values as HCL, one variable per line
+// (nested objects and lists span several lines), sorted by name.
+func FormatVarValues(values map[string]cty.Value) string {
+	if len(values) == 0 {
+		return "No variables set"
+	}
+
+	var b strings.Builder
+	for _, name := range sortedKeys(values) {
+		b.WriteString(name + " = " + FormatValue(values[name], 0) + "\n")
+	}
+	return b.String()
+}
+
+// FormatValue renders a value in HCL syntax. indent is the nesting level
+// of the value, used to indent the elements of collections.
+func FormatValue(value cty.Value, indent int) string {
+	if !value.IsKnown() {
+		return "(known after apply)"
+	}
+	if value.IsNull() {
+		return "null"
+	}
+
+	ty := value.Type()
+	pad := strings.Repeat("  ", indent)
+
+	switch {
+	case ty == cty.String:
+		return strconv.Quote(value.AsString())
+	case ty == cty.Number:
+		return value.AsBigFloat().Text('f', -1)
+	case ty == cty.Bool:
+		if value.True() {
+			return "true"
+		}
+		return "false"
+	case ty.IsListType() || ty.IsTupleType() || ty.IsSetType():
+		if value.LengthInt() == 0 {
+			return "[]"
+		}
+		var b strings.Builder
+		b.WriteString("[\n")
+		for it := value.ElementIterator(); it.Next(); {
+			_, element := it.Element()
+			b.WriteString(pad + "  " + FormatValue(element, indent+1) + ",\n")
+		}
+		b.WriteString(pad + "]")
+		return b.String()
+	case ty.IsMapType() || ty.IsObjectType():
+		if value.LengthInt() == 0 {
+			return "{}"
+		}
+		var b strings.Builder
+		b.WriteString("{\n")
+		// Map and object iteration is in lexical key order
+		for it := value.ElementIterator(); it.Next(); {
+			key, element := it.Element()
+			b.WriteString(pad + "  " + formatKey(key.AsString()) + " = " + FormatValue(element, indent+1) + "\n")
+		}
+		b.WriteString(pad + "}")
+		return b.String()
+	default:
+		return ty.FriendlyName()
+	}
+}
+
+// formatKey quotes object keys that aren't valid HCL identifiers.
+func formatKey(key string) string {
+	for i, r := range key {
+		isLetter := r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+		isDigit := r >= '0' && r <= '9'
+		if !isLetter && !(i > 0 && (isDigit || r == '-')) {
+			return strconv.Quote(key)
+		}
+	}
+	if key == "" {
+		return `""`
+	}
+	return key
+}
@@ -0,0 +1,100 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestParseVarFile(t *testing.T) {
+	dir := t.TempDir()
+	hclPath := writeFile(t, dir, "dev.tfvars", `
+region   = "eu-west-1"
+replicas = 3
+enabled  = true
+zones    = ["a", "b"]
+tags = {
+  team = "platform"
+  "cost-center" = 42
+}
+nothing = null
+`, 0o644)
+	jsonPath := writeFile(t, dir, "dev.tfvars.json", `{"region": "eu-west-1", "replicas": 3, "zones": ["a", "b"], "tags": {"team": "platform"}}`, 0o644)
+
+	values, err := ParseVarFile(hclPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checks := map[string]cty.Value{
+		"region":   cty.StringVal("eu-west-1"),
+		"replicas": cty.NumberIntVal(3),
+		"enabled":  cty.True,
+		"zones":    cty.TupleVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")}),
+		"tags":     cty.ObjectVal(map[string]cty.Value{"team": cty.StringVal("platform"), "cost-center": cty.NumberIntVal(42)}),
+		"nothing":  cty.NullVal(cty.DynamicPseudoType),
+	}
+	if len(values) != len(checks) {
+		t.Errorf("ParseVarFile() returned %d values, want %d", len(values), len(checks))
+	}
+	for name, want := range checks {
+		if got, ok := values[name]; !ok || !got.RawEquals(want) {
+			t.Errorf("ParseVarFile()[%q] = %#v, want %#v", name, got, want)
+		}
+	}
+
+	jsonValues, err := ParseVarFile(jsonPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := FormatVarValues(jsonValues); got != "region = \"eu-west-1\"\nreplicas = 3\ntags = {\n  team = \"platform\"\n}\nzones = [\n  \"a\",\n  \"b\",\n]\n" {
+		t.Errorf("FormatVarValues() of the JSON file = %q", got)
+	}
+}
+
+func TestParseVarFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"syntax.tfvars":      `region = "eu-west-1`,
+		"block.tfvars":       "settings {\n  a = 1\n}\n",
+		"reference.tfvars":   `region = var.default_region`,
+		"broken.tfvars.json": `{"region": }`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseVarFile(writeFile(t, dir, name, content, 0o644)); err == nil {
+				t.Errorf("ParseVarFile(%s) succeeded, want an error", name)
+			}
+		})
+	}
+	if _, err := ParseVarFile(dir + "/missing.tfvars"); err == nil {
+		t.Error("ParseVarFile() of a missing file succeeded, want an error")
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		value cty.Value
+		want  string
+	}{
+		{name: "string", value: cty.StringVal(`say "hi"`), want: `"say \"hi\""`},
+		{name: "number", value: cty.NumberFloatVal(1.5), want: "1.5"},
+		{name: "bool", value: cty.False, want: "false"},
+		{name: "null", value: cty.NullVal(cty.String), want: "null"},
+		{name: "unknown", value: cty.UnknownVal(cty.String), want: "(known after apply)"},
+		{name: "empty list", value: cty.ListValEmpty(cty.String), want: "[]"},
+		{name: "empty map", value: cty.MapValEmpty(cty.String), want: "{}"},
+		{
+			name:  "nested",
+			value: cty.ObjectVal(map[string]cty.Value{"1st": cty.ListVal([]cty.Value{cty.NumberIntVal(1)}), "b-c": cty.True}),
+			want:  "{\n  \"1st\" = [\n    1,\n  ]\n  b-c = true\n}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatValue(tt.value, 0); got != tt.want {
+				t.Errorf("FormatValue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+// Package terraform provides functionality for detecting, discovering,
+// and interacting with Terraform projects and resources.
+//
+// This file contains var file comparison logic:
+// - DiffVarValues: Key-by-key deep diff of two sets of variable values
+// - FormatVarDiff: Format a diff for UI display
+package terraform
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// VarDiffKind describes how a value differs between two var files.
+type VarDiffKind int
+
+const (
+	DiffOnlyLeft  VarDiffKind = iota // set in the left file only
+	DiffOnlyRight                    // set in the right file only
+	DiffChanged                      // set in both with different values
+)
+
+// VarDiff is a single difference between two var files.
+type VarDiff struct {
+	Path  string // variable name, with the nested path for deep diffs (e.g. "tags.Env", "subnets[2]")
+	Kind  VarDiffKind
+	Left  cty.Value // cty.NilVal for DiffOnlyRight
+	Right cty.Value // cty.NilVal for DiffOnlyLeft
+}
+
+// DiffVarValues compares two sets of variable values key by key.
+// Maps/objects and lists/tuples present on both sides are compared recursively,
+// so a changed tag is reported as "tags.Env" rather than the whole "tags" map.
+func DiffVarValues(left, right map[string]cty.Value) []VarDiff {
+	var diffs []VarDiff
+
+	names := sortedKeys(left)
+	for _, name := range sortedKeys(right) {
+		if _, ok := left[name]; !ok {
+			names = append(names, name)
+		}
+	}
+
+	for _, name := range names {
+		leftValue, inLeft := left[name]
+		rightValue, inRight := right[name]
+		switch {
+		case !inRight:
+			diffs = append(diffs, VarDiff{Path: name, Kind: DiffOnlyLeft, Left: leftValue})
+		case !inLeft:
+			diffs = append(diffs, VarDiff{Path: name, Kind: DiffOnlyRight, Right: rightValue})
+		default:
+			diffs = append(diffs, diffValues(name, leftValue, rightValue)...)
+		}
+	}
+	return diffs
+}
+
+// diffValues compares two values at path, recursing into collections of the same kind.
+func diffValues(path string, left, right cty.Value) []VarDiff {
+	if valuesEqual(left, right) {
+		return nil
+	}
+	if left.IsNull() || right.IsNull() || !left.IsKnown() || !right.IsKnown() {
+		return []VarDiff{{Path: path, Kind: DiffChanged, Left: left, Right: right}}
+	}
+
+	leftType, rightType := left.Type(), right.Type()
+
+	if isMapLike(leftType) && isMapLike(rightType) {
+		leftMap, rightMap := left.AsValueMap(), right.AsValueMap()
+		var diffs []VarDiff
+		for _, key := range sortedKeys(leftMap) {
+			childPath := path + "." + formatKey(key)
+			if rightValue, ok := rightMap[key]; ok {
+				diffs = append(diffs, diffValues(childPath, leftMap[key], rightValue)...)
+			} else {
+				diffs = append(diffs, VarDiff{Path: childPath, Kind: DiffOnlyLeft, Left: leftMap[key]})
+			}
+		}
+		for _, key := range sortedKeys(rightMap) {
+			if _, ok := leftMap[key]; !ok {
+				diffs = append(diffs, VarDiff{Path: path + "." + formatKey(key), Kind: DiffOnlyRight, Right: rightMap[key]})
+			}
+		}
+		return diffs
+	}
+
+	if isListLike(leftType) && isListLike(rightType) {
+		leftList, rightList := left.AsValueSlice(), right.AsValueSlice()
+		var diffs []VarDiff
+		for i := 0; i < len(leftList) || i < len(rightList); i++ {
+			childPath := path + "[" + strconv.Itoa(i) + "]"
+			switch {
+			case i >= len(rightList):
+				diffs = append(diffs, VarDiff{Path: childPath, Kind: DiffOnlyLeft, Left: leftList[i]})
+			case i >= len(leftList):
+				diffs = append(diffs, VarDiff{Path: childPath, Kind: DiffOnlyRight, Right: rightList[i]})
+			default:
+				diffs = append(diffs, diffValues(childPath, leftList[i], rightList[i])...)
+			}
+		}
+		return diffs
+	}
+
+	return []VarDiff{{Path: path, Kind: DiffChanged, Left: left, Right: right}}
+}
+
+// valuesEqual compares two values, treating maps and objects (lists and tuples)
+// with the same content as equal since HCL literals produce objects and tuples.
+func valuesEqual(left, right cty.Value) bool {
+	if left.IsNull() || right.IsNull() {
+		return left.IsNull() && right.IsNull()
+	}
+	if !left.IsKnown() || !right.IsKnown() {
+		return false
+	}
+	if isMapLike(left.Type()) && isMapLike(right.Type()) {
+		leftMap, rightMap := left.AsValueMap(), right.AsValueMap()
+		if len(leftMap) != len(rightMap) {
+			return false
+		}
+		for key, leftValue := range leftMap {
+			rightValue, ok := rightMap[key]
+			if !ok || !valuesEqual(leftValue, rightValue) {
+				return false
+			}
+		}
+		return true
+	}
+	if isListLike(left.Type()) && isListLike(right.Type()) {
+		leftList, rightList := left.AsValueSlice(), right.AsValueSlice()
+		if len(leftList) != len(rightList) {
+			return false
+		}
+		for i := range leftList {
+			if !valuesEqual(leftList[i], rightList[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return left.RawEquals(right)
+}
+
+func isMapLike(ty cty.Type) bool {
+	return ty.IsMapType() || ty.IsObjectType()
+}
+
+func isListLike(ty cty.Type) bool {
+	return ty.IsListType() || ty.IsTupleType() || ty.IsSetType()
+}
+
+// FormatValueInline renders a value in HCL syntax on a single line.
+func FormatValueInline(value cty.Value) string {
+	if !value.IsKnown() || value.IsNull() {
+		return FormatValue(value, 0)
+	}
+	ty := value.Type()
+	switch {
+	case isListLike(ty):
+		var parts []string
+		for _, element := range value.AsValueSlice() {
+			parts = append(parts, FormatValueInline(element))
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	case isMapLike(ty):
+		if value.LengthInt() == 0 {
+			return "{}"
+		}
+		var parts []string
+		for it := value.ElementIterator(); it.Next(); {
+			key, element := it.Element()
+			parts = append(parts, formatKey(key.AsString())+" = "+FormatValueInline(element))
+		}
+		return "{ " + strings.Join(parts, ", ") + " }"
+	default:
+		return FormatValue(value, 0)
+	}
+}
+
+// FormatVarDiff creates a human-readable diff, grouped by kind:
+//
+//	- debug = true                      (only in left)
+//	+ replicas = 3                      (only in right)
+//	~ tags.Env: "dev" → "prod"          (changed)
+func FormatVarDiff(leftName, rightName string, diffs []VarDiff) string {
+	if len(diffs) == 0 {
+		return "No differences between " + leftName + " and " + rightName
+	}
+
+	var onlyLeft, onlyRight, changed []string
+	for _, diff := range diffs {
+		switch diff.Kind {
+		case DiffOnlyLeft:
+			onlyLeft = append(onlyLeft, "  - "+diff.Path+" = "+FormatValueInline(diff.Left))
+		case DiffOnlyRight:
+			onlyRight = append(onlyRight, "  + "+diff.Path+" = "+FormatValueInline(diff.Right))
+		case DiffChanged:
+			changed = append(changed, "  ~ "+diff.Path+": "+FormatValueInline(diff.Left)+" → "+FormatValueInline(diff.Right))
+		}
+	}
+
+	result := strconv.Itoa(len(diffs)) + " difference(s)\n"
+	if len(onlyLeft) > 0 {
+		result += "\nOnly in " + leftName + ":\n" + strings.Join(onlyLeft, "\n") + "\n"
+	}
+	if len(onlyRight) > 0 {
+		result += "\nOnly in " + rightName + ":\n" + strings.Join(onlyRight, "\n") + "\n"
+	}
+	if len(changed) > 0 {
+		result += "\nChanged (" + leftName + " → " + rightName + "):\n" + strings.Join(changed, "\n") + "\n"
+	}
+	return result
+}
@@ -0,0 +1,83 @@
+package terraform
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestDiffVarValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		left  map[string]cty.Value
+		right map[string]cty.Value
+		want  []string // kind prefix and path of each diff, e.g. "~ tags.Env"
+	}{
+		{
+			name:  "identical",
+			left:  map[string]cty.Value{"region": cty.StringVal("eu-west-1")},
+			right: map[string]cty.Value{"region": cty.StringVal("eu-west-1")},
+		},
+		{
+			name:  "only on one side",
+			left:  map[string]cty.Value{"debug": cty.True, "region": cty.StringVal("eu-west-1")},
+			right: map[string]cty.Value{"region": cty.StringVal("eu-west-1"), "replicas": cty.NumberIntVal(3)},
+			want:  []string{"- debug", "+ replicas"},
+		},
+		{
+			name:  "changed scalar",
+			left:  map[string]cty.Value{"replicas": cty.NumberIntVal(1)},
+			right: map[string]cty.Value{"replicas": cty.NumberIntVal(3)},
+			want:  []string{"~ replicas"},
+		},
+		{
+			name: "nested map keys",
+			left: map[string]cty.Value{"tags": cty.ObjectVal(map[string]cty.Value{
+				"Env":   cty.StringVal("dev"),
+				"Owner": cty.StringVal("team"),
+			})},
+			right: map[string]cty.Value{"tags": cty.ObjectVal(map[string]cty.Value{
+				"Env":  cty.StringVal("prod"),
+				"Cost": cty.StringVal("42"),
+			})},
+			want: []string{"~ tags.Env", "- tags.Owner", "+ tags.Cost"},
+		},
+		{
+			name:  "list elements",
+			left:  map[string]cty.Value{"subnets": cty.TupleVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")})},
+			right: map[string]cty.Value{"subnets": cty.TupleVal([]cty.Value{cty.StringVal("a"), cty.StringVal("c"), cty.StringVal("d")})},
+			want:  []string{"~ subnets[1]", "+ subnets[2]"},
+		},
+		{
+			name:  "map and object with the same content are equal",
+			left:  map[string]cty.Value{"tags": cty.MapVal(map[string]cty.Value{"Env": cty.StringVal("dev")})},
+			right: map[string]cty.Value{"tags": cty.ObjectVal(map[string]cty.Value{"Env": cty.StringVal("dev")})},
+		},
+		{
+			name:  "null against a value",
+			left:  map[string]cty.Value{"vpc_id": cty.NullVal(cty.String)},
+			right: map[string]cty.Value{"vpc_id": cty.StringVal("vpc-123")},
+			want:  []string{"~ vpc_id"},
+		},
+		{
+			name:  "different kinds of values",
+			left:  map[string]cty.Value{"zones": cty.StringVal("a")},
+			right: map[string]cty.Value{"zones": cty.TupleVal([]cty.Value{cty.StringVal("a")})},
+			want:  []string{"~ zones"},
+		},
+	}
+
+	prefixes := map[VarDiffKind]string{DiffOnlyLeft: "- ", DiffOnlyRight: "+ ", DiffChanged: "~ "}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, diff := range DiffVarValues(tt.left, tt.right) {
+				got = append(got, prefixes[diff.Kind]+diff.Path)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffVarValues() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
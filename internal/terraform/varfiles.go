@@ -2,7 +2,8 @@
 // and interacting with Terraform projects and resources.
 //
 // This file contains var file discovery logic:
-// - DiscoverVarFiles: Find .tfvars files in conventional locations
+// - DiscoverVarFiles: Find .tfvars/.tfvars.json files under the configured roots
+// - disambiguateEnvNames: Make environment names unique across directories
 // - GetVarFileDisplayNames: Extract environment names for UI display
 package terraform
 
@@ -10,68 +11,170 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/Nicolas-Rigaudy/lazytf/internal/config"
 )
 
-// DiscoverVarFiles scans a Terraform project directory for .tfvars files
-// in conventional locations (root, variables/, env/, tfvars/).
-// Returns a list of discovered variable files.
-func DiscoverVarFiles(projectPath string) ([]VarFile, error) {
+// genericVarFileNames are file names that don't say which environment they belong to.
+// For these the environment comes from the parent directory (envs/dev/terraform.tfvars -> "dev").
+var genericVarFileNames = map[string]bool{
+	"terraform": true,
+	"variables": true,
+	"vars":      true,
+}
+
+// DiscoverVarFiles scans a Terraform project directory for .tfvars and .tfvars.json files
+// under the given roots (project-relative). A root ending in "/**" is scanned recursively,
+// e.g. "variables/**" finds variables/eu-west-1/prod.tfvars; other roots only look at
+// their direct children. When the conventions set a var file glob it replaces the roots.
+// Files matching the backend glob are backend configs and are skipped, and the
+// conventions' env pattern, when it matches, names the environment.
+// Returns a list of discovered variable files with unique environment names.
+func DiscoverVarFiles(projectPath string, roots []string, conventions config.NamingConventions) ([]VarFile, error) {
 	var varFiles []VarFile
+	var parents [][]string // per var file, directory segments usable to disambiguate its env name
+	seen := make(map[string]bool)
 
-	scanDirs := []string{".", "variables", "env", "tfvars"}
+	if conventions.VarFileGlob != "" {
+		roots = []string{globRoot(conventions.VarFileGlob) + "/**"}
+	}
 
-	for _, dir := range scanDirs {
+	for _, root := range roots {
+		recursive := strings.HasSuffix(root, "/**")
+		dir := filepath.Clean(strings.TrimSuffix(root, "/**"))
 		fullDirPath := filepath.Join(projectPath, dir)
 
 		info, err := os.Stat(fullDirPath)
-		if err != nil {
+		if err != nil || !info.IsDir() {
 			// Directory doesn't exist, skip it
 			continue
 		}
-		if !info.IsDir() {
-			continue
-		}
 
-		entries, err := os.ReadDir(fullDirPath)
-		if err != nil {
-			// Can't read directory, skip it
-			continue
-		}
+		filepath.WalkDir(fullDirPath, func(path string, entry os.DirEntry, err error) error {
+			if err != nil {
+				return nil // Skip paths we can't read
+			}
 
-		for _, entry := range entries {
 			if entry.IsDir() {
-				continue
+				if path == fullDirPath {
+					return nil
+				}
+				if !recursive || strings.HasPrefix(entry.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
 			}
 
-			if filepath.Ext(entry.Name()) != ".tfvars" {
-				continue
+			if !isVarFileName(entry.Name()) || seen[path] {
+				return nil
 			}
 
-			var relativePath string
-			if dir == "." {
-				relativePath = entry.Name()
-			} else {
-				relativePath = filepath.Join(dir, entry.Name())
+			relativePath, err := filepath.Rel(projectPath, path)
+			if err != nil {
+				relativePath = path
 			}
 
-			// Extract environment name from filename
-			// e.g., "dev2.tfvars" -> "dev2"
-			envName := strings.TrimSuffix(entry.Name(), ".tfvars")
+			if conventions.VarFileGlob != "" && !MatchGlob(conventions.VarFileGlob, relativePath) {
+				return nil
+			}
+			if MatchGlob(conventions.BackendFileGlob, relativePath) {
+				return nil
+			}
+			seen[path] = true
+
+			// Extract environment name from filename, or from the directory for generic names
+			// e.g., "dev2.tfvars" -> "dev2", "envs/dev/terraform.tfvars" -> "dev"
+			dirSegments := strings.Split(filepath.ToSlash(filepath.Dir(relativePath)), "/")
+			if dirSegments[0] == "." {
+				dirSegments = nil
+			}
+			envName, matched := ExtractEnv(conventions.VarFileEnv, relativePath)
+			if !matched {
+				envName = trimVarFileExt(entry.Name())
+			}
+			if !matched && genericVarFileNames[envName] && len(dirSegments) > 0 && filepath.Dir(path) != fullDirPath {
+				envName = dirSegments[len(dirSegments)-1]
+				dirSegments = dirSegments[:len(dirSegments)-1]
+			}
 
 			varFile := VarFile{
-				Name:     entry.Name(),
-				Path:     relativePath,
-				FullPath: filepath.Join(projectPath, relativePath),
-				EnvName:  envName,
+				Name:       entry.Name(),
+				Path:       relativePath,
+				FullPath:   path,
+				EnvName:    envName,
+				AutoLoaded: filepath.Dir(path) == filepath.Clean(projectPath) && isAutoLoadedVarFile(entry.Name()),
 			}
 
 			varFiles = append(varFiles, varFile)
-		}
+			parents = append(parents, dirSegments)
+			return nil
+		})
 	}
 
+	disambiguateEnvNames(varFiles, parents)
 	return varFiles, nil
 }
 
+// isVarFileName reports whether a file is a var file (.tfvars or .tfvars.json).
+func isVarFileName(name string) bool {
+	return strings.HasSuffix(name, ".tfvars") || strings.HasSuffix(name, ".tfvars.json")
+}
+
+// trimVarFileExt removes the .tfvars or .tfvars.json extension from a file name.
+func trimVarFileExt(name string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(name, ".json"), ".tfvars")
+}
+
+// disambiguateEnvNames makes environment names unique by prepending parent directories
+// to the names shared by several files, one level at a time:
+// variables/eu-west-1/prod.tfvars and variables/eu-west-2/prod.tfvars
+// become "eu-west-1/prod" and "eu-west-2/prod".
+// parents[i] holds the directory segments available to prefix varFiles[i].
+// Files of the same directory sharing a name (prod.tfvars and prod.tfvars.json) are told
+// apart by extension first: the first keeps the name, the others get theirs ("prod (.tfvars.json)").
+func disambiguateEnvNames(varFiles []VarFile, parents [][]string) {
+	// Parent directories can't tell apart files of the same directory
+	kept := make(map[string]int)
+	for i := range varFiles {
+		key := varFiles[i].EnvName + "|" + strings.Join(parents[i], "/")
+		first, ok := kept[key]
+		if !ok {
+			kept[key] = i
+			continue
+		}
+		suffix := strings.TrimPrefix(varFiles[i].Name, trimVarFileExt(varFiles[i].Name))
+		if suffix == "" || suffix == strings.TrimPrefix(varFiles[first].Name, trimVarFileExt(varFiles[first].Name)) {
+			suffix = varFiles[i].Name // same extension, e.g. two names matched by a naming convention
+		}
+		varFiles[i].EnvName += " (" + suffix + ")"
+	}
+
+	baseNames := make([]string, len(varFiles))
+	for i := range varFiles {
+		baseNames[i] = varFiles[i].EnvName
+	}
+
+	for depth := 1; ; depth++ {
+		counts := make(map[string]int)
+		for i := range varFiles {
+			counts[varFiles[i].EnvName]++
+		}
+
+		changed := false
+		for i := range varFiles {
+			if counts[varFiles[i].EnvName] < 2 || depth > len(parents[i]) {
+				continue
+			}
+			prefix := strings.Join(parents[i][len(parents[i])-depth:], "/")
+			varFiles[i].EnvName = prefix + "/" + baseNames[i]
+			changed = true
+		}
+		if !changed {
+			return
+		}
+	}
+}
+
 // GetVarFileDisplayNames extracts environment names from var files for UI display.
 // This helper keeps UI code clean and maintains separation of concerns.
 func GetVarFileDisplayNames(varFiles []VarFile) []string {
@@ -0,0 +1,72 @@
+package terraform
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDisambiguateEnvNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		envs    []string
+		files   []string // file names, "<env>.tfvars" when nil
+		parents [][]string
+		want    []string
+	}{
+		{
+			name:    "unique names are kept",
+			envs:    []string{"dev", "prod"},
+			parents: [][]string{{"variables"}, {"variables"}},
+			want:    []string{"dev", "prod"},
+		},
+		{
+			name:    "one parent level",
+			envs:    []string{"prod", "prod", "dev"},
+			parents: [][]string{{"variables", "eu-west-1"}, {"variables", "eu-west-2"}, {"variables", "eu-west-1"}},
+			want:    []string{"eu-west-1/prod", "eu-west-2/prod", "dev"},
+		},
+		{
+			name:    "two parent levels",
+			envs:    []string{"prod", "prod"},
+			parents: [][]string{{"aws", "eu", "shared"}, {"gcp", "eu", "shared"}},
+			want:    []string{"aws/eu/shared/prod", "gcp/eu/shared/prod"},
+		},
+		{
+			name:    "a root file keeps its name",
+			envs:    []string{"prod", "prod"},
+			parents: [][]string{{}, {"eu-west-1"}},
+			want:    []string{"prod", "eu-west-1/prod"},
+		},
+		{
+			name:    "same directory, different extensions",
+			envs:    []string{"prod", "prod", "dev"},
+			files:   []string{"prod.tfvars", "prod.tfvars.json", "dev.tfvars"},
+			parents: [][]string{{"variables"}, {"variables"}, {"variables"}},
+			want:    []string{"prod", "prod (.tfvars.json)", "dev"},
+		},
+		{
+			name:    "same directory, same extension",
+			envs:    []string{"prod", "prod"},
+			files:   []string{"prod.tfvars", "prod-v2.tfvars"},
+			parents: [][]string{{}, {}},
+			want:    []string{"prod", "prod (prod-v2.tfvars)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			varFiles := make([]VarFile, len(tt.envs))
+			for i, env := range tt.envs {
+				varFiles[i].EnvName = env
+				varFiles[i].Name = env + ".tfvars"
+				if tt.files != nil {
+					varFiles[i].Name = tt.files[i]
+				}
+			}
+			disambiguateEnvNames(varFiles, tt.parents)
+			if got := GetVarFileDisplayNames(varFiles); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("disambiguateEnvNames() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
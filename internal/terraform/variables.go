@@ -0,0 +1,224 @@
+// Package terraform provides functionality for detecting, discovering,
+// and interacting with Terraform projects and resources.
+//
+// This file contains variable declaration parsing and coverage checks:
+// - ParseVariables: Read variable blocks (type, default, sensitive, nullable) from .tf files
+// - LoadEnvironmentValues: Merge an environment's layered var files
+// - CheckCoverage: Compare declared variables with the values an environment sets
+// - FormatCoverageReport: Format coverage findings for UI display
+package terraform
+
+import (
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// Variable is a `variable "name" {}` block declared in the project.
+type Variable struct {
+	Name       string
+	Type       cty.Type  // cty.DynamicPseudoType when no type is declared
+	Default    cty.Value // cty.NilVal when there is no default
+	HasDefault bool      // false means the variable is required
+	Sensitive  bool
+	Nullable   bool // true unless `nullable = false`
+	File       string
+}
+
+// TypeMismatch is a value that can't be converted to its variable's declared type.
+type TypeMismatch struct {
+	Name     string
+	Expected string // declared type, e.g. "list of string"
+	Problem  string // why the value doesn't fit
+}
+
+// CoverageReport lists the problems of an environment's values against the declared variables.
+type CoverageReport struct {
+	Missing        []string       // required variables without a value
+	Undeclared     []string       // values set for variables that aren't declared
+	TypeMismatches []TypeMismatch // values that don't fit the declared type
+}
+
+// ParseVariables reads all variable blocks from the project's .tf/.tofu files.
+// Attributes that can't be evaluated statically are ignored.
+func ParseVariables(projectPath string) []Variable {
+	var variables []Variable
+
+	for _, block := range topLevelBlocks(parseProjectSources(projectPath), "variable") {
+		if len(block.Labels) != 1 {
+			continue
+		}
+
+		variable := Variable{
+			Name:     block.Labels[0],
+			Type:     cty.DynamicPseudoType,
+			Nullable: true,
+			File:     filepath.Base(block.Range().Filename),
+		}
+
+		if attr, ok := block.Body.Attributes["type"]; ok {
+			if ty, diags := typeexpr.TypeConstraint(attr.Expr); !diags.HasErrors() {
+				variable.Type = ty
+			}
+		}
+
+		if attr, ok := block.Body.Attributes["default"]; ok {
+			if value, diags := attr.Expr.Value(nil); !diags.HasErrors() {
+				variable.Default = value
+			}
+			// An unevaluable default is still a default, the variable isn't required
+			variable.HasDefault = true
+		}
+
+		if attr, ok := block.Body.Attributes["sensitive"]; ok {
+			if value, diags := attr.Expr.Value(nil); !diags.HasErrors() && value.Type() == cty.Bool && value.IsKnown() && !value.IsNull() {
+				variable.Sensitive = value.True()
+			}
+		}
+
+		if attr, ok := block.Body.Attributes["nullable"]; ok {
+			if value, diags := attr.Expr.Value(nil); !diags.HasErrors() && value.Type() == cty.Bool && value.IsKnown() && !value.IsNull() {
+				variable.Nullable = value.True()
+			}
+		}
+
+		variables = append(variables, variable)
+	}
+
+	sort.Slice(variables, func(i, j int) bool { return variables[i].Name < variables[j].Name })
+	return variables
+}
+
+// MergeVarFiles parses the given var files and merges their values, later files winning.
+// A file that appears more than once is only read once.
+func MergeVarFiles(paths []string) (map[string]cty.Value, error) {
+	merged := make(map[string]cty.Value)
+	seen := make(map[string]bool)
+
+	for _, path := range paths {
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+
+		values, err := ParseVarFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for name, value := range values {
+			merged[name] = value
+		}
+	}
+	return merged, nil
+}
+
+// LoadEnvironmentValues returns the values Terraform would see for an environment
+// given its layered var files (see ResolveVarFileLayers), later layers winning.
+func LoadEnvironmentValues(layers []VarFile) (map[string]cty.Value, error) {
+	paths := make([]string, len(layers))
+	for i, layer := range layers {
+		paths[i] = layer.FullPath
+	}
+	return MergeVarFiles(paths)
+}
+
+// CheckCoverage compares declared variables with the values an environment sets.
+// Variables set through TF_VAR_<name> in env (the "KEY=VALUE" environment commands
+// run with) count as provided.
+func CheckCoverage(variables []Variable, values map[string]cty.Value, env []string) CoverageReport {
+	var report CoverageReport
+	declared := make(map[string]bool, len(variables))
+
+	for _, variable := range variables {
+		declared[variable.Name] = true
+
+		value, isSet := values[variable.Name]
+		if !isSet {
+			if !variable.HasDefault && envValue(env, "TF_VAR_"+variable.Name) == "" {
+				report.Missing = append(report.Missing, variable.Name)
+			}
+			continue
+		}
+
+		if value.IsNull() {
+			if !variable.Nullable {
+				report.TypeMismatches = append(report.TypeMismatches, TypeMismatch{
+					Name:     variable.Name,
+					Expected: variable.Type.FriendlyNameForConstraint(),
+					Problem:  "null is not allowed (nullable = false)",
+				})
+			}
+			continue
+		}
+
+		if _, err := convert.Convert(value, variable.Type); err != nil {
+			report.TypeMismatches = append(report.TypeMismatches, TypeMismatch{
+				Name:     variable.Name,
+				Expected: variable.Type.FriendlyNameForConstraint(),
+				Problem:  err.Error(),
+			})
+		}
+	}
+
+	for _, name := range sortedKeys(values) {
+		if !declared[name] {
+			report.Undeclared = append(report.Undeclared, name)
+		}
+	}
+
+	return report
+}
+
+// IsClean reports whether the environment has no coverage problems.
+func (r CoverageReport) IsClean() bool {
+	return len(r.Missing) == 0 && len(r.Undeclared) == 0 && len(r.TypeMismatches) == 0
+}
+
+// Summary returns a short description for the sidebar, e.g. "2 missing, 1 type".
+// Returns "" when the report is clean.
+func (r CoverageReport) Summary() string {
+	var parts []string
+	if len(r.Missing) > 0 {
+		parts = append(parts, strconv.Itoa(len(r.Missing))+" missing")
+	}
+	if len(r.TypeMismatches) > 0 {
+		parts = append(parts, strconv.Itoa(len(r.TypeMismatches))+" type")
+	}
+	if len(r.Undeclared) > 0 {
+		parts = append(parts, strconv.Itoa(len(r.Undeclared))+" undeclared")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// FormatCoverageReport creates a human-readable description of coverage problems.
+func FormatCoverageReport(r CoverageReport) string {
+	if r.IsClean() {
+		return "✅ All required variables are set"
+	}
+
+	result := ""
+	if len(r.Missing) > 0 {
+		result += "❌ Missing required variables:\n"
+		for _, name := range r.Missing {
+			result += "  • " + name + "\n"
+		}
+	}
+	if len(r.TypeMismatches) > 0 {
+		result += "❌ Type mismatches:\n"
+		for _, mismatch := range r.TypeMismatches {
+			result += "  • " + mismatch.Name + " (expected " + mismatch.Expected + "): " + mismatch.Problem + "\n"
+		}
+	}
+	if len(r.Undeclared) > 0 {
+		result += "⚠️  Values for undeclared variables:\n"
+		for _, name := range r.Undeclared {
+			result += "  • " + name + "\n"
+		}
+	}
+	return strings.TrimSuffix(result, "\n")
+}
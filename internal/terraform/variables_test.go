@@ -0,0 +1,108 @@
+package terraform
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestCheckCoverage(t *testing.T) {
+	variables := []Variable{
+		{Name: "region", Type: cty.String, Nullable: true},
+		{Name: "replicas", Type: cty.Number, Nullable: true},
+		{Name: "tags", Type: cty.Map(cty.String), HasDefault: true, Nullable: true},
+		{Name: "vpc_id", Type: cty.String, Nullable: false},
+		{Name: "from_env", Type: cty.String, Nullable: true},
+	}
+	env := []string{"TF_VAR_from_env=unset", "HOME=/home/me", "TF_VAR_from_env=set", "TF_VAR_region="}
+
+	tests := []struct {
+		name       string
+		values     map[string]cty.Value
+		missing    []string
+		undeclared []string
+		mismatches []string
+	}{
+		{
+			name: "all set",
+			values: map[string]cty.Value{
+				"region":   cty.StringVal("eu-west-1"),
+				"replicas": cty.NumberIntVal(3),
+				"vpc_id":   cty.StringVal("vpc-123"),
+			},
+		},
+		{
+			name: "missing required variables",
+			values: map[string]cty.Value{
+				"region": cty.StringVal("eu-west-1"),
+			},
+			missing: []string{"replicas", "vpc_id"},
+		},
+		{
+			name: "empty TF_VAR value doesn't count",
+			values: map[string]cty.Value{
+				"replicas": cty.NumberIntVal(3),
+				"vpc_id":   cty.StringVal("vpc-123"),
+			},
+			missing: []string{"region"},
+		},
+		{
+			name: "undeclared values",
+			values: map[string]cty.Value{
+				"region":   cty.StringVal("eu-west-1"),
+				"replicas": cty.NumberIntVal(3),
+				"vpc_id":   cty.StringVal("vpc-123"),
+				"zone":     cty.StringVal("a"),
+				"debug":    cty.True,
+			},
+			undeclared: []string{"debug", "zone"},
+		},
+		{
+			name: "convertible values are accepted",
+			values: map[string]cty.Value{
+				"region":   cty.StringVal("eu-west-1"),
+				"replicas": cty.StringVal("3"),
+				"vpc_id":   cty.StringVal("vpc-123"),
+			},
+		},
+		{
+			name: "type mismatches",
+			values: map[string]cty.Value{
+				"region":   cty.StringVal("eu-west-1"),
+				"replicas": cty.StringVal("three"),
+				"vpc_id":   cty.StringVal("vpc-123"),
+				"tags":     cty.ListVal([]cty.Value{cty.StringVal("a")}),
+			},
+			mismatches: []string{"replicas", "tags"},
+		},
+		{
+			name: "null for a non-nullable variable",
+			values: map[string]cty.Value{
+				"region":   cty.NullVal(cty.String),
+				"replicas": cty.NumberIntVal(3),
+				"vpc_id":   cty.NullVal(cty.String),
+			},
+			mismatches: []string{"vpc_id"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := CheckCoverage(variables, tt.values, env)
+			if !reflect.DeepEqual(report.Missing, tt.missing) {
+				t.Errorf("Missing = %v, want %v", report.Missing, tt.missing)
+			}
+			if !reflect.DeepEqual(report.Undeclared, tt.undeclared) {
+				t.Errorf("Undeclared = %v, want %v", report.Undeclared, tt.undeclared)
+			}
+			var mismatches []string
+			for _, mismatch := range report.TypeMismatches {
+				mismatches = append(mismatches, mismatch.Name)
+			}
+			if !reflect.DeepEqual(mismatches, tt.mismatches) {
+				t.Errorf("TypeMismatches = %v, want %v", mismatches, tt.mismatches)
+			}
+		})
+	}
+}
@@ -0,0 +1,350 @@
+// Package terraform provides functionality for detecting, discovering,
+// and interacting with Terraform projects and resources.
+//
+// This file contains engine binary version resolution logic:
+// - DiscoverVersionRequirements: Read required_version, version files and .tool-versions
+// - DiscoverInstalledBinaries: Find versioned binaries in tfenv/tofuenv/asdf style install dirs
+// - ResolveBinary: Pick the newest installed binary satisfying every requirement
+// - FormatBinaryResolution: Format the resolved version for UI display
+// - FormatVersionDetails: Format requirements and resolved binary for the project details
+package terraform
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-version"
+
+	"github.com/Nicolas-Rigaudy/lazytf/internal/config"
+)
+
+// VersionRequirement is a version constraint declared by a project.
+type VersionRequirement struct {
+	Constraint string // e.g. ">= 1.5.0, < 2.0.0" or "1.5.7"
+	Source     string // where it was declared (e.g. "versions.tf", ".terraform-version")
+}
+
+// InstalledBinary is a Terraform or OpenTofu executable found on this machine.
+type InstalledBinary struct {
+	Version string // e.g. "1.5.7"
+	Path    string // absolute path to the executable
+}
+
+// BinaryResolution is the outcome of matching a project's requirements against installed binaries.
+type BinaryResolution struct {
+	Engine       Engine
+	Requirements []VersionRequirement
+	Binary       string // executable to run (the engine's PATH binary when nothing better was found)
+	Version      string // version of Binary, "" if unknown
+	Satisfied    bool   // false when no installed binary satisfies every requirement
+	Warning      string // human-readable explanation when Satisfied is false or the version couldn't be read
+}
+
+// DiscoverVersionRequirements collects the version constraints that apply to a project:
+//   - required_version in terraform {} blocks of the project's .tf files
+//   - the nearest .terraform-version (tfenv) or .opentofu-version (tofuenv) file, searching parent directories
+//   - the nearest .tool-versions file with a terraform or opentofu entry (asdf), searching parent directories
+func DiscoverVersionRequirements(projectPath string, engine Engine) []VersionRequirement {
+	var requirements []VersionRequirement
+
+	files := parseProjectSources(projectPath)
+	for _, block := range terraformBlocks(files) {
+		constraint := stringAttribute(block.Body, "required_version")
+		if constraint == "" {
+			continue
+		}
+		requirements = append(requirements, VersionRequirement{
+			Constraint: constraint,
+			Source:     filepath.Base(block.Range().Filename),
+		})
+	}
+
+	versionFile, asdfTool := ".terraform-version", "terraform"
+	if engine == EngineOpenTofu {
+		versionFile, asdfTool = ".opentofu-version", "opentofu"
+	}
+
+	if pin, source := findVersionFile(projectPath, versionFile); pin != "" {
+		requirements = append(requirements, VersionRequirement{Constraint: pin, Source: source})
+	}
+
+	if pin, source := findToolVersions(projectPath, asdfTool); pin != "" {
+		requirements = append(requirements, VersionRequirement{Constraint: pin, Source: source})
+	}
+
+	return requirements
+}
+
+// findVersionFile looks for a tfenv/tofuenv version file in projectPath and its parents.
+// Returns the pinned version and the file path relative to the project.
+// Non-numeric keywords ("latest", "min-required", ...) are ignored.
+func findVersionFile(projectPath, fileName string) (string, string) {
+	for dir := projectPath; ; dir = filepath.Dir(dir) {
+		path := filepath.Join(dir, fileName)
+		if data, err := os.ReadFile(path); err == nil {
+			pin := strings.TrimSpace(string(data))
+			if _, err := version.NewVersion(pin); err != nil {
+				return "", ""
+			}
+			return pin, relativeSource(projectPath, path)
+		}
+		if filepath.Dir(dir) == dir {
+			return "", ""
+		}
+	}
+}
+
+// findToolVersions looks for an asdf .tool-versions file with an entry for the given tool
+// in projectPath and its parents. Returns the first listed version and the file path.
+func findToolVersions(projectPath, tool string) (string, string) {
+	for dir := projectPath; ; dir = filepath.Dir(dir) {
+		path := filepath.Join(dir, ".tool-versions")
+		if pin, ok := readToolVersion(path, tool); ok {
+			return pin, relativeSource(projectPath, path)
+		}
+		if filepath.Dir(dir) == dir {
+			return "", ""
+		}
+	}
+}
+
+// readToolVersion reads a single tool entry from a .tool-versions file.
+// Example line: "terraform 1.5.7 1.4.6" -> "1.5.7"
+func readToolVersion(path, tool string) (string, bool) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", false
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != tool {
+			continue
+		}
+		if _, err := version.NewVersion(fields[1]); err != nil {
+			return "", false
+		}
+		return fields[1], true
+	}
+	return "", false
+}
+
+// relativeSource shortens a version file path for display.
+func relativeSource(projectPath, path string) string {
+	if rel, err := filepath.Rel(projectPath, path); err == nil {
+		return rel
+	}
+	return path
+}
+
+// DiscoverInstalledBinaries lists versioned engine binaries in the given install directories.
+// Supported layouts:
+//   - tfenv/tofuenv: <dir>/<version>/terraform (or tofu)
+//   - asdf:          <dir>/<version>/bin/terraform (or tofu)
+func DiscoverInstalledBinaries(installDirs []string, engine Engine) []InstalledBinary {
+	var binaries []InstalledBinary
+
+	for _, dir := range installDirs {
+		dir, err := config.ExpandHome(dir)
+		if err != nil {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			if _, err := version.NewVersion(entry.Name()); err != nil {
+				continue
+			}
+			for _, candidate := range []string{
+				filepath.Join(dir, entry.Name(), engine.Binary()),
+				filepath.Join(dir, entry.Name(), "bin", engine.Binary()),
+			} {
+				if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+					binaries = append(binaries, InstalledBinary{Version: entry.Name(), Path: candidate})
+					break
+				}
+			}
+		}
+	}
+
+	return binaries
+}
+
+var (
+	binaryVersionCache   = make(map[string]string)
+	binaryVersionCacheMu sync.Mutex
+)
+
+// pathBinary returns the engine executable found in PATH along with its version.
+// The version is read from `<binary> version -json` once per executable and cached.
+// OpenTofu reports its version under the same "terraform_version" key.
+// The bool is false when there is no such executable; the error tells why its
+// version couldn't be read (failures aren't cached, so they are retried next time).
+func pathBinary(engine Engine) (InstalledBinary, bool, error) {
+	path, err := exec.LookPath(engine.Binary())
+	if err != nil {
+		return InstalledBinary{}, false, nil
+	}
+
+	binaryVersionCacheMu.Lock()
+	defer binaryVersionCacheMu.Unlock()
+
+	if v, ok := binaryVersionCache[path]; ok {
+		return InstalledBinary{Version: v, Path: path}, true, nil
+	}
+
+	cmd := exec.Command(path, "version", "-json")
+	// Skip the upgrade check, it needs network access and slows down startup
+	cmd.Env = append(os.Environ(), "CHECKPOINT_DISABLE=1")
+	output, err := cmd.Output()
+	if err != nil {
+		return InstalledBinary{Path: path}, true, fmt.Errorf("failed to run %s version -json: %v", path, err)
+	}
+
+	var parsed struct {
+		Version string `json:"terraform_version"`
+	}
+	if err := json.Unmarshal(output, &parsed); err != nil {
+		return InstalledBinary{Path: path}, true, fmt.Errorf("unexpected %s version -json output: %v", path, err)
+	}
+	if parsed.Version == "" {
+		return InstalledBinary{Path: path}, true, fmt.Errorf("%s version -json reported no version", path)
+	}
+	binaryVersionCache[path] = parsed.Version
+
+	return InstalledBinary{Version: parsed.Version, Path: path}, true, nil
+}
+
+// ResolveBinary picks the binary to run for a project.
+// The newest binary satisfying every requirement wins; install dirs and PATH are both considered.
+// Without requirements the PATH binary is used as-is.
+func ResolveBinary(projectPath string, engine Engine, installDirs []string) BinaryResolution {
+	resolution := BinaryResolution{
+		Engine:       engine,
+		Requirements: DiscoverVersionRequirements(projectPath, engine),
+		Binary:       engine.Binary(),
+	}
+
+	fromPath, hasPathBinary, versionErr := pathBinary(engine)
+	if hasPathBinary {
+		resolution.Version = fromPath.Version
+	}
+
+	if len(resolution.Requirements) == 0 {
+		resolution.Satisfied = true
+		if versionErr != nil {
+			resolution.Warning = versionErr.Error()
+		}
+		return resolution
+	}
+
+	var constraints []version.Constraints
+	for _, req := range resolution.Requirements {
+		parsed, err := version.NewConstraint(req.Constraint)
+		if err != nil {
+			resolution.Warning = fmt.Sprintf("invalid version constraint %q in %s", req.Constraint, req.Source)
+			return resolution
+		}
+		constraints = append(constraints, parsed)
+	}
+
+	candidates := DiscoverInstalledBinaries(installDirs, engine)
+	if hasPathBinary && fromPath.Version != "" {
+		candidates = append(candidates, fromPath)
+	}
+
+	// Newest first, so the first satisfying candidate wins
+	sort.SliceStable(candidates, func(i, j int) bool {
+		vi, _ := version.NewVersion(candidates[i].Version)
+		vj, _ := version.NewVersion(candidates[j].Version)
+		return vi.GreaterThan(vj)
+	})
+
+	for _, candidate := range candidates {
+		v, err := version.NewVersion(candidate.Version)
+		if err != nil {
+			continue
+		}
+		if satisfiesAll(v, constraints) {
+			if candidate.Path != fromPath.Path {
+				resolution.Binary = candidate.Path
+			}
+			resolution.Version = candidate.Version
+			resolution.Satisfied = true
+			return resolution
+		}
+	}
+
+	resolution.Warning = "No installed " + engine.Binary() + " satisfies " + FormatRequirements(resolution.Requirements)
+	if versionErr != nil {
+		resolution.Warning += " (" + versionErr.Error() + ")"
+	}
+	return resolution
+}
+
+// satisfiesAll reports whether v matches every constraint set.
+func satisfiesAll(v *version.Version, constraints []version.Constraints) bool {
+	for _, c := range constraints {
+		if !c.Check(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// FormatRequirements formats requirements as "<constraint> (<source>)", comma separated.
+func FormatRequirements(requirements []VersionRequirement) string {
+	parts := make([]string, len(requirements))
+	for i, req := range requirements {
+		parts[i] = req.Constraint + " (" + req.Source + ")"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// FormatBinaryResolution creates a short description of the resolved binary for the header.
+// Examples: "1.5.7", "1.5.7 ⚠", "unknown"
+func FormatBinaryResolution(resolution BinaryResolution) string {
+	v := resolution.Version
+	if v == "" {
+		v = "unknown"
+	}
+	if !resolution.Satisfied {
+		v += " ⚠"
+	}
+	return v
+}
+
+// FormatVersionDetails creates a multi-line description of the project's version
+// requirements and the binary that will be used.
+func FormatVersionDetails(resolution BinaryResolution) string {
+	result := "Resolved: " + FormatBinaryResolution(resolution) + " (" + resolution.Binary + ")\n"
+
+	if len(resolution.Requirements) == 0 {
+		result += "Required: no constraint declared"
+	} else {
+		result += "Required: " + FormatRequirements(resolution.Requirements)
+	}
+
+	if resolution.Warning != "" {
+		result += "\n⚠️  " + resolution.Warning
+	}
+	return result
+}
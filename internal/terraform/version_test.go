@@ -0,0 +1,157 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// writeFile creates a file below dir, creating parent directories as needed.
+func writeFile(t *testing.T, dir, name, content string, perm os.FileMode) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), perm); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// fakeTerraformInPath puts a terraform script printing output in a fresh PATH.
+func fakeTerraformInPath(t *testing.T, script string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake binaries are shell scripts")
+	}
+	dir := t.TempDir()
+	path := writeFile(t, dir, "terraform", "#!/bin/sh\n"+script+"\n", 0o755)
+	t.Setenv("PATH", dir)
+	return path
+}
+
+func TestResolveBinary(t *testing.T) {
+	installDir := t.TempDir()
+	for _, v := range []string{"1.4.6", "1.5.7", "1.6.0"} {
+		writeFile(t, installDir, filepath.Join(v, "terraform"), "", 0o755)
+	}
+	writeFile(t, installDir, filepath.Join("1.3.0", "bin", "terraform"), "", 0o755)
+	writeFile(t, installDir, filepath.Join("not-a-version", "terraform"), "", 0o755)
+
+	tests := []struct {
+		name          string
+		files         map[string]string
+		wantVersion   string
+		wantInstalled bool // Binary points into installDir rather than PATH
+		wantSatisfied bool
+		wantWarning   string // substring, "" for no warning
+	}{
+		{
+			name:          "no constraint uses PATH",
+			wantVersion:   "1.7.0",
+			wantSatisfied: true,
+		},
+		{
+			name:          "newest binary satisfying required_version",
+			files:         map[string]string{"versions.tf": `terraform { required_version = "~> 1.5.0" }`},
+			wantVersion:   "1.5.7",
+			wantInstalled: true,
+			wantSatisfied: true,
+		},
+		{
+			name:          "PATH binary wins when newest",
+			files:         map[string]string{"versions.tf": `terraform { required_version = ">= 1.5.0" }`},
+			wantVersion:   "1.7.0",
+			wantSatisfied: true,
+		},
+		{
+			name: "every requirement must hold",
+			files: map[string]string{
+				"versions.tf":        `terraform { required_version = ">= 1.4.0" }`,
+				".terraform-version": "1.4.6\n",
+			},
+			wantVersion:   "1.4.6",
+			wantInstalled: true,
+			wantSatisfied: true,
+		},
+		{
+			name:          "asdf layout",
+			files:         map[string]string{".tool-versions": "nodejs 20.0.0\nterraform 1.3.0 # pinned\n"},
+			wantVersion:   "1.3.0",
+			wantInstalled: true,
+			wantSatisfied: true,
+		},
+		{
+			name:        "nothing satisfies",
+			files:       map[string]string{"versions.tf": `terraform { required_version = "< 1.0.0" }`},
+			wantVersion: "1.7.0",
+			wantWarning: "No installed terraform satisfies < 1.0.0 (versions.tf)",
+		},
+		{
+			name:        "invalid constraint",
+			files:       map[string]string{"versions.tf": `terraform { required_version = "not a constraint" }`},
+			wantVersion: "1.7.0",
+			wantWarning: `invalid version constraint "not a constraint" in versions.tf`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeTerraformInPath(t, `echo '{"terraform_version": "1.7.0"}'`)
+			projectPath := t.TempDir()
+			for name, content := range tt.files {
+				writeFile(t, projectPath, name, content, 0o644)
+			}
+
+			resolution := ResolveBinary(projectPath, EngineTerraform, []string{installDir})
+			if resolution.Version != tt.wantVersion {
+				t.Errorf("Version = %q, want %q", resolution.Version, tt.wantVersion)
+			}
+			if installed := strings.HasPrefix(resolution.Binary, installDir); installed != tt.wantInstalled {
+				t.Errorf("Binary = %q, want installed binary %v", resolution.Binary, tt.wantInstalled)
+			}
+			if resolution.Satisfied != tt.wantSatisfied {
+				t.Errorf("Satisfied = %v, want %v", resolution.Satisfied, tt.wantSatisfied)
+			}
+			if tt.wantWarning == "" && resolution.Warning != "" || !strings.Contains(resolution.Warning, tt.wantWarning) {
+				t.Errorf("Warning = %q, want %q", resolution.Warning, tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestResolveBinaryUnreadableVersion(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+	}{
+		{name: "command fails", script: "exit 1"},
+		{name: "not JSON", script: "echo Terraform v1.7.0"},
+		{name: "no version", script: `echo '{}'`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := fakeTerraformInPath(t, tt.script)
+
+			// Without constraints the PATH binary is still used, with a warning
+			resolution := ResolveBinary(t.TempDir(), EngineTerraform, nil)
+			if !resolution.Satisfied || resolution.Version != "" || !strings.Contains(resolution.Warning, path) {
+				t.Errorf("ResolveBinary() satisfied=%v version=%q warning=%q, want satisfied, no version and a warning naming %s",
+					resolution.Satisfied, resolution.Version, resolution.Warning, path)
+			}
+
+			// With constraints an unreadable PATH binary can't satisfy them
+			projectPath := t.TempDir()
+			writeFile(t, projectPath, ".terraform-version", "1.7.0", 0o644)
+			resolution = ResolveBinary(projectPath, EngineTerraform, nil)
+			if resolution.Satisfied || !strings.Contains(resolution.Warning, path) {
+				t.Errorf("ResolveBinary() satisfied=%v warning=%q, want unsatisfied with a warning naming %s",
+					resolution.Satisfied, resolution.Warning, path)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"os"
+
+	"github.com/Nicolas-Rigaudy/lazytf/internal/auth"
+	"github.com/aymanbagabas/go-osc52/v2"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// showDeviceAuthorization shows (or updates) the modal telling the user where to
+// complete a no-browser SSO login
+func (m *Model) showDeviceAuthorization() {
+	auth := *m.deviceAuth
+	message := "Open this URL in a browser on any machine:\n\n" + auth.URL + "\n"
+	actions := []ModalAction{{
+		Key:   "u",
+		Label: "[u] Copy URL",
+		Do:    func() tea.Msg { return CopyToClipboardMsg{What: "URL", Text: auth.URL} },
+	}}
+	if auth.Code != "" {
+		message += "\nThen enter the code:\n\n" + auth.Code + "\n"
+		actions = append(actions, ModalAction{
+			Key:   "c",
+			Label: "[c] Copy code",
+			Do:    func() tea.Msg { return CopyToClipboardMsg{What: "code", Text: auth.Code} },
+		})
+	}
+	message += "\nThis closes by itself once the login completes."
+
+	m.modal.Show(ModalState{
+		Type:    ModalInfo,
+		Title:   "🔑 " + m.loginProviderName() + " Login",
+		Message: message,
+		Actions: actions,
+	})
+}
+
+// loginProviderName returns the display name of the provider whose login is running
+func (m Model) loginProviderName() string {
+	if provider := auth.Find(m.loginProvider); provider != nil {
+		return provider.Name()
+	}
+	return "AWS SSO"
+}
+
+// closeDeviceAuthorization hides the login modal once the login command is done
+func (m *Model) closeDeviceAuthorization() {
+	if m.deviceAuth == nil {
+		return
+	}
+	m.deviceAuth = nil
+	if m.modal.Kind() == ModalInfo {
+		m.modal.Close()
+	}
+}
+
+// copyToClipboard copies text with an OSC 52 escape sequence, which works over SSH
+// and inside tmux/screen as long as the terminal supports it
+func copyToClipboard(text string) {
+	sequence := osc52.New(text)
+	if os.Getenv("TMUX") != "" {
+		sequence = sequence.Tmux()
+	} else if os.Getenv("STY") != "" {
+		sequence = sequence.Screen()
+	}
+	sequence.WriteTo(os.Stderr)
+}
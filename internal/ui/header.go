@@ -35,11 +35,29 @@ type HeaderModel struct {
 type InfoHeaderData struct {
 	ProjectName     string
 	EnvName         string
+	Region          string // AWS region commands for the env run in, "" when unknown
 	IsInitialized   bool
+	Engine          string // engine display name ("Terraform" or "OpenTofu")
+	Version         string // resolved engine version, "" when no project is selected
+	VersionWarning  bool   // true when the resolved binary doesn't satisfy the project's constraints
+	AWSProfile      string // profile passed to commands as AWS_PROFILE, "" when none is selected
+	AWSWarning      bool   // true when the profile doesn't exist
+	AWSAccount      string // account of the resolved caller identity, "" when unknown
+	AccountWarning  bool   // true when the account doesn't match the environment's expected account
+	SSOStatus       string // time to expiry of the SSO token, e.g. "3h12m left", "" without SSO
+	MFAStatus       string // time to expiry of MFA credentials, e.g. "52m left", "code needed", "" without MFA
+	Clouds          []CloudStatus
 	LastCommand     string
 	LastCommandTime time.Time
 }
 
+// CloudStatus is the login state of a cloud other than AWS the project uses
+type CloudStatus struct {
+	Name     string // e.g. "Azure"
+	Text     string // e.g. "user@example.com (my-subscription)"
+	LoggedIn bool
+}
+
 func NewHeader() HeaderModel {
 	return HeaderModel{
 		Width:  0,
@@ -54,6 +72,12 @@ func (h HeaderModel) View(data InfoHeaderData) string {
 		"  ",
 		headerLabelStyle.Render("Env: "),
 		headerValueStyle.Render(data.EnvName),
+		func() string {
+			if data.Region == "" {
+				return ""
+			}
+			return headerValueStyle.Render(" (" + data.Region + ")")
+		}(),
 		"  ",
 		func() string {
 			if data.IsInitialized {
@@ -61,6 +85,62 @@ func (h HeaderModel) View(data InfoHeaderData) string {
 			}
 			return headerErrorStyle.Render("❌ Not Initialized")
 		}(),
+		func() string {
+			if data.Version == "" {
+				return ""
+			}
+			if data.VersionWarning {
+				return "  " + headerLabelStyle.Render(data.Engine+": ") + headerErrorStyle.Render(data.Version)
+			}
+			return "  " + headerLabelStyle.Render(data.Engine+": ") + headerValueStyle.Render(data.Version)
+		}(),
+		func() string {
+			if data.AWSProfile == "" {
+				return ""
+			}
+			if data.AWSWarning {
+				return "  " + headerLabelStyle.Render("AWS: ") + headerErrorStyle.Render(data.AWSProfile+" (missing)")
+			}
+			return "  " + headerLabelStyle.Render("AWS: ") + headerValueStyle.Render(data.AWSProfile)
+		}(),
+		func() string {
+			if data.AWSAccount == "" {
+				return ""
+			}
+			if data.AccountWarning {
+				return "  " + headerLabelStyle.Render("Account: ") + headerErrorStyle.Render(data.AWSAccount+" ⚠")
+			}
+			return "  " + headerLabelStyle.Render("Account: ") + headerValueStyle.Render(data.AWSAccount)
+		}(),
+		func() string {
+			switch data.SSOStatus {
+			case "":
+				return ""
+			case "expired", "not logged in":
+				return "  " + headerLabelStyle.Render("SSO: ") + headerErrorStyle.Render(data.SSOStatus)
+			}
+			return "  " + headerLabelStyle.Render("SSO: ") + headerValueStyle.Render(data.SSOStatus)
+		}(),
+		func() string {
+			switch data.MFAStatus {
+			case "":
+				return ""
+			case "code needed":
+				return "  " + headerLabelStyle.Render("MFA: ") + headerErrorStyle.Render(data.MFAStatus)
+			}
+			return "  " + headerLabelStyle.Render("MFA: ") + headerValueStyle.Render(data.MFAStatus)
+		}(),
+		func() string {
+			result := ""
+			for _, cloud := range data.Clouds {
+				if cloud.LoggedIn {
+					result += "  " + headerLabelStyle.Render(cloud.Name+": ") + headerValueStyle.Render(cloud.Text)
+				} else {
+					result += "  " + headerLabelStyle.Render(cloud.Name+": ") + headerErrorStyle.Render(cloud.Text)
+				}
+			}
+			return result
+		}(),
 	)
 	line2 := ""
 	if data.LastCommand != "" {
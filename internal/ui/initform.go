@@ -0,0 +1,140 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Nicolas-Rigaudy/lazytf/internal/config"
+	"github.com/Nicolas-Rigaudy/lazytf/internal/terraform"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Fields of the init options form, in display order
+const (
+	initFieldReconfigure = iota
+	initFieldMigrateState
+	initFieldForceCopy
+	initFieldUpgrade
+	initFieldNoBackend
+	initFieldLockfile
+	initFieldPluginDir
+	initFieldBackendConfig
+)
+
+// initFormFields builds the init options form pre-filled with the given defaults for an environment
+func initFormFields(defaults config.InitDefaults, envName string) []FormField {
+	return []FormField{
+		initFieldReconfigure:  {Label: "Reconfigure (-reconfigure)", Kind: FormToggle, Checked: defaults.Reconfigure},
+		initFieldMigrateState: {Label: "Migrate state (-migrate-state)", Kind: FormToggle, Checked: defaults.MigrateState},
+		initFieldForceCopy:    {Label: "Copy state without asking (-force-copy)", Kind: FormToggle, Checked: defaults.ForceCopy},
+		initFieldUpgrade:      {Label: "Upgrade providers and modules (-upgrade)", Kind: FormToggle, Checked: defaults.Upgrade},
+		initFieldNoBackend:    {Label: "Skip backend (-backend=false)", Kind: FormToggle, Checked: defaults.NoBackend},
+		initFieldLockfile: {
+			Label:   "Lockfile",
+			Kind:    FormChoice,
+			Value:   defaults.LockfileMode,
+			Options: []string{terraform.LockfileDefault, terraform.LockfileReadonly},
+		},
+		initFieldPluginDir: {Label: "Plugin dir", Kind: FormText, Value: defaults.PluginDir, Placeholder: "(none)"},
+		initFieldBackendConfig: {
+			Label:       "Backend config",
+			Kind:        FormText,
+			Value:       strings.Join(defaults.BackendConfig[envName], "\n"),
+			Placeholder: "key=value, one per line (ctrl+n)",
+			Multiline:   true,
+		},
+	}
+}
+
+// initDefaultsFromForm reads the submitted init options form of an environment
+func initDefaultsFromForm(fields []FormField, envName string) config.InitDefaults {
+	var backendConfig []string
+	// One pair per line, values may contain commas
+	for _, pair := range strings.Split(fields[initFieldBackendConfig].Value, "\n") {
+		if pair = strings.TrimSpace(pair); pair != "" {
+			backendConfig = append(backendConfig, pair)
+		}
+	}
+
+	return config.InitDefaults{
+		Reconfigure:   fields[initFieldReconfigure].Checked,
+		MigrateState:  fields[initFieldMigrateState].Checked,
+		ForceCopy:     fields[initFieldForceCopy].Checked,
+		Upgrade:       fields[initFieldUpgrade].Checked,
+		NoBackend:     fields[initFieldNoBackend].Checked,
+		LockfileMode:  fields[initFieldLockfile].Value,
+		PluginDir:     strings.TrimSpace(fields[initFieldPluginDir].Value),
+		BackendConfig: map[string][]string{envName: backendConfig},
+	}
+}
+
+// initOptionsFromDefaults converts remembered defaults into init options for an environment and backend
+func initOptionsFromDefaults(defaults config.InitDefaults, envName string, backend terraform.BackendVarFile) terraform.InitOptions {
+	return terraform.InitOptions{
+		BackendConfigFile: backend,
+		BackendConfig:     defaults.BackendConfig[envName],
+		Reconfigure:       defaults.Reconfigure,
+		MigrateState:      defaults.MigrateState,
+		ForceCopy:         defaults.ForceCopy,
+		Upgrade:           defaults.Upgrade,
+		NoBackend:         defaults.NoBackend,
+		PluginDir:         defaults.PluginDir,
+		LockfileMode:      defaults.LockfileMode,
+		Input:             false,
+	}
+}
+
+// projectInitDefaults returns the init options remembered for the selected project
+func (m Model) projectInitDefaults() config.InitDefaults {
+	if defaults := m.config.ProjectSettings(m.selectedProject.Path).Init; defaults != nil {
+		return *defaults
+	}
+	return config.DefaultInitDefaults()
+}
+
+// showInitForm opens the init options form for an environment and backend
+// errorText is shown below the fields, used to report validation errors
+func (m *Model) showInitForm(envName string, backend terraform.BackendVarFile, defaults config.InitDefaults, errorText string) {
+	m.modal.Show(ModalState{
+		Type:      ModalForm,
+		Title:     "Init Options",
+		Message:   "Environment: " + envName + "    Backend: " + backend.Name,
+		Fields:    initFormFields(defaults, envName),
+		ErrorText: errorText,
+		OnSubmit: func(fields []FormField) tea.Msg {
+			return InitOptionsEditedMsg{
+				EnvName:  envName,
+				Backend:  backend,
+				Defaults: initDefaultsFromForm(fields, envName),
+			}
+		},
+	})
+}
+
+// rememberInitDefaults stores the init options used for an environment of the selected project
+// in the config file. Backend config pairs are kept per environment, without the ones holding secrets.
+// Nothing is written when the config file couldn't be loaded, as saving the defaults used
+// instead would overwrite every setting in it
+func (m *Model) rememberInitDefaults(defaults config.InitDefaults, envName string) error {
+	if m.configErr != nil {
+		return fmt.Errorf("the config file could not be loaded (%v), not overwriting it", m.configErr)
+	}
+	settings := m.config.ProjectSettings(m.selectedProject.Path)
+
+	// Keep the pairs saved for the other environments
+	backendConfig := make(map[string][]string)
+	if settings.Init != nil {
+		for env, pairs := range settings.Init.BackendConfig {
+			backendConfig[env] = pairs
+		}
+	}
+	delete(backendConfig, envName)
+	if pairs := terraform.StorableBackendConfig(defaults.BackendConfig[envName]); len(pairs) > 0 {
+		backendConfig[envName] = pairs
+	}
+	defaults.BackendConfig = backendConfig
+
+	settings.Init = &defaults
+	m.config.SetProjectSettings(m.selectedProject.Path, settings)
+	return config.Save(m.config)
+}
@@ -0,0 +1,171 @@
+package ui
+
+import (
+	"github.com/Nicolas-Rigaudy/lazytf/internal/auth"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// loginProviders returns the providers the selected project logs in to,
+// AWS when no project is selected or it uses none of the supported clouds
+func (m Model) loginProviders() []auth.Provider {
+	if m.selectedProject != nil && len(m.cloudProviders) > 0 {
+		return m.cloudProviders
+	}
+	return []auth.Provider{auth.AWS{}}
+}
+
+// selectLoginProvider asks which cloud to log in to when the project uses several
+func (m *Model) selectLoginProvider(noBrowser bool) tea.Cmd {
+	providers := m.loginProviders()
+	if len(providers) == 1 {
+		return m.showLogins(providers[0], noBrowser)
+	}
+
+	ctx := m.authContext(noBrowser)
+	var items []string
+	for _, provider := range providers {
+		item := provider.Name()
+		if status := auth.RecentStatus(provider, ctx); status.Text != "" {
+			item += " — " + status.Text
+		}
+		items = append(items, item)
+	}
+	m.modal.Show(ModalState{
+		Type:    ModalSelect,
+		Title:   "Log In",
+		Message: "Select the cloud to log in to:",
+		Items:   items,
+		OnSelect: func(index int) tea.Msg {
+			return LoginProviderSelectedMsg{Provider: providers[index].ID(), NoBrowser: noBrowser}
+		},
+	})
+	return nil
+}
+
+// showLogins starts the provider's login, or asks which one to use when there are several
+// Providers with a ContextPicker also offer to switch context once logged in
+func (m *Model) showLogins(provider auth.Provider, noBrowser bool) tea.Cmd {
+	// Assume-role profiles with MFA log in with a code rather than a command
+	if provider.ID() == "aws" {
+		if profile := m.mfaProfile(); profile != nil {
+			m.showMFAPrompt(profile, nil, "")
+			return nil
+		}
+	}
+
+	ctx := m.authContext(noBrowser)
+	logins := provider.Logins(ctx)
+	if len(logins) == 0 {
+		errorText := "There is no way to log in to " + provider.Name() + " configured."
+		if provider.ID() == "aws" {
+			errorText = "No AWS SSO sessions found in your AWS config file. Please configure at least one SSO session in ~/.aws/config " +
+				"before attempting to log in."
+		}
+		m.modal.Show(ModalState{
+			Type:      ModalError,
+			Title:     "❌ No " + provider.Name() + " Login Found",
+			ErrorText: errorText,
+		})
+		return nil
+	}
+
+	var items []string
+	for _, login := range logins {
+		items = append(items, login.Label)
+	}
+	picker, canPick := provider.(auth.ContextPicker)
+	if canPick && auth.RecentStatus(provider, ctx).LoggedIn {
+		items = append(items, "Select the "+picker.ContextName()+"...")
+	}
+
+	providerID := provider.ID()
+	if len(items) == 1 {
+		return func() tea.Msg { return RunLoginMsg{Provider: providerID, Login: logins[0]} }
+	}
+	m.modal.Show(ModalState{
+		Type:    ModalSelect,
+		Title:   provider.Name() + " Login",
+		Message: "Select how to log in:",
+		Items:   items,
+		OnSelect: func(index int) tea.Msg {
+			if index >= len(logins) {
+				return PickAuthContextMsg{Provider: providerID}
+			}
+			return RunLoginMsg{Provider: providerID, Login: logins[index]}
+		},
+	})
+	return nil
+}
+
+// showAuthContextPicker lists the contexts of a provider, e.g. the Azure subscriptions
+func (m *Model) showAuthContextPicker(provider auth.Provider) {
+	picker, ok := provider.(auth.ContextPicker)
+	if !ok {
+		return
+	}
+	choices, err := picker.Contexts(m.authContext(false))
+	if err != nil || len(choices) == 0 {
+		errorText := "No " + picker.ContextName() + " to select, log in first."
+		if err != nil {
+			errorText = err.Error()
+		} else if m.selectedProject != nil && m.selectedVarFile != nil {
+			errorText = "No " + picker.ContextName() + " to select: the environment's " + picker.ContextName() +
+				" is set in the config, or you need to log in first."
+		}
+		m.modal.Show(ModalState{
+			Type:      ModalError,
+			Title:     "❌ No " + provider.Name() + " " + picker.ContextName(),
+			ErrorText: errorText,
+		})
+		return
+	}
+
+	ctx := m.authContext(false)
+	var items []string
+	selected := 0
+	for i, choice := range choices {
+		items = append(items, choice.Label)
+		if choice.ID == ctx.Picked[provider.ID()] {
+			selected = i
+		}
+	}
+	providerID, key := provider.ID(), regionKey(ctx)
+	m.modal.Show(ModalState{
+		Type:     ModalSelect,
+		Title:    provider.Name() + " " + picker.ContextName(),
+		Message:  "Select the " + picker.ContextName() + " commands run with:",
+		Items:    items,
+		Selected: selected,
+		OnSelect: func(index int) tea.Msg {
+			return AuthContextPickedMsg{Key: key, Provider: providerID, ID: choices[index].ID, Label: picker.ContextName() + " " + choices[index].Label}
+		},
+	})
+}
+
+// finishLogin runs once a login command is done: cached statuses are dropped, the
+// AWS identity is resolved again, and the context is offered for picking when the
+// login gave access to several (e.g. Azure subscriptions)
+func (m *Model) finishLogin(completed bool) tea.Cmd {
+	if m.loginProvider == "" {
+		return nil
+	}
+	provider := auth.Find(m.loginProvider)
+	m.loginProvider = ""
+	auth.ForgetStatuses()
+	if provider == nil || !completed {
+		return nil
+	}
+
+	if provider.ID() == "aws" {
+		return m.resolveIdentity(nil)
+	}
+	picker, ok := provider.(auth.ContextPicker)
+	if !ok || m.authContext(false).Picked[provider.ID()] != "" || !provider.Status(m.authContext(false)).LoggedIn {
+		return nil
+	}
+	if choices, err := picker.Contexts(m.authContext(false)); err != nil || len(choices) < 2 {
+		return nil
+	}
+	providerID := provider.ID()
+	return func() tea.Msg { return PickAuthContextMsg{Provider: providerID} }
+}
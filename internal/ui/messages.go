@@ -1,7 +1,11 @@
 package ui
 
 import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Nicolas-Rigaudy/lazytf/internal/auth"
 	"github.com/Nicolas-Rigaudy/lazytf/internal/aws"
+	"github.com/Nicolas-Rigaudy/lazytf/internal/config"
 	"github.com/Nicolas-Rigaudy/lazytf/internal/terraform"
 )
 
@@ -27,9 +31,159 @@ type InitBackendSelectedMsg struct {
 
 type RunInitMsg struct {
 	ProjectPath string
+	EnvName     string
 	Options     terraform.InitOptions
+	Defaults    *config.InitDefaults // Remembered for the project once the init runs, nil to keep the saved ones
+}
+
+// LoginProviderSelectedMsg is sent when the cloud to log in to was picked
+type LoginProviderSelectedMsg struct {
+	Provider  string // auth provider ID
+	NoBrowser bool
+}
+
+// RunLoginMsg starts a login command of an auth provider
+type RunLoginMsg struct {
+	Provider string
+	Login    auth.Login
+}
+
+// RunWithoutLoginMsg runs a command again without the login check, after the user chose to
+// ignore the warning (credentials the providers can't see, e.g. a managed identity)
+type RunWithoutLoginMsg struct {
+	Retry tea.Msg
+}
+
+// PickAuthContextMsg asks for the context of a provider to be picked, e.g. the Azure subscription
+type PickAuthContextMsg struct {
+	Provider string
+}
+
+// AuthContextPickedMsg is sent when a provider's context was picked
+type AuthContextPickedMsg struct {
+	Key      string // environment it was picked for, see regionKey
+	Provider string
+	ID       string
+	Label    string
+}
+
+// CopyToClipboardMsg asks for text to be copied to the terminal's clipboard
+type CopyToClipboardMsg struct {
+	What string // shown in the status bar, e.g. "URL"
+	Text string
+}
+
+// IdentityResolvedMsg carries the result of `aws sts get-caller-identity`
+// Key identifies the AWS environment it was resolved for, Retry is re-sent once the identity is known
+type IdentityResolvedMsg struct {
+	Key      string
+	Identity *aws.CallerIdentity
+	Err      error
+	Retry    tea.Msg
+}
+
+// AWSProfileSelectedMsg is sent when a profile is picked in the AWS profile modal
+type AWSProfileSelectedMsg struct {
+	Profile string
+}
+
+// CommandEnvSelectedMsg is sent once the environment for a plan/apply is known
+// EnvName is empty when the project has no var files
+type CommandEnvSelectedMsg struct {
+	Command string // "plan" or "apply"
+	EnvName string
+}
+
+type RunPlanMsg struct {
+	ProjectPath string
+	Options     terraform.PlanOptions
+	Apply       *savedPlan // set when the plan is saved for an apply, confirmed once it is done
+}
+
+// SavedPlanShownMsg is sent when a plan saved for an apply was rendered with `show`
+type SavedPlanShownMsg struct {
+	Plan savedPlan
+	Text string
+	Err  error
+}
+
+type RunApplyMsg struct {
+	ProjectPath string
+	Options     terraform.ApplyOptions
+}
+
+// GraphLoadedMsg is sent when `terraform graph` finished and was parsed
+type GraphLoadedMsg struct {
+	Graph *terraform.Graph
+	Err   error
+}
+
+type GraphNodeSelectedMsg struct {
+	Node string
+}
+
+// InitOptionsEditedMsg is sent when the init options form is submitted
+type InitOptionsEditedMsg struct {
+	EnvName  string
+	Backend  terraform.BackendVarFile
+	Defaults config.InitDefaults
+}
+
+// CompareLeftSelectedMsg is sent when the first environment to compare was picked
+type CompareLeftSelectedMsg struct {
+	Left int // index in the var files
 }
 
-type RunAWSSSOLoginMsg struct {
+// CompareVarFilesMsg is sent when both environments to compare were picked
+type CompareVarFilesMsg struct {
+	Left  int // index in the var files
+	Right int
+}
+
+// ListSSORolesMsg asks for the accounts and roles of an SSO session, to generate profiles
+// Without a session, the user is asked to pick one first
+type ListSSORolesMsg struct {
 	Session *aws.SSOSession
 }
+
+// SSORolesListedMsg carries the accounts and roles available through an SSO session
+type SSORolesListedMsg struct {
+	Session *aws.SSOSession
+	Roles   []aws.SSOAccountRole
+	Err     error
+}
+
+// AddSSOProfilesMsg is sent when the roles to generate profiles for were picked
+type AddSSOProfilesMsg struct {
+	Session *aws.SSOSession
+	Roles   []aws.SSOAccountRole
+	Region  string // region of the new profiles, none when empty
+}
+
+// AssumeRoleMsg is sent when the MFA code of an assume-role profile was entered
+// Retry is the command to run again once the credentials are obtained
+type AssumeRoleMsg struct {
+	Profile string
+	Code    string
+	Retry   tea.Msg
+}
+
+// RoleAssumedMsg carries the temporary credentials of an assume-role profile
+type RoleAssumedMsg struct {
+	Profile     string
+	Credentials *aws.RoleCredentials
+	Err         error
+	Retry       tea.Msg
+}
+
+// AWSRegionSelectedMsg is sent when a region is picked for an environment, "" going back to the automatic one
+type AWSRegionSelectedMsg struct {
+	Key    string // see regionKey
+	Region string
+}
+
+// BinaryResolvedMsg carries the engine binary resolved for a project
+type BinaryResolvedMsg struct {
+	ProjectPath string
+	Binary      terraform.BinaryResolution
+}
@@ -0,0 +1,47 @@
+package ui
+
+import (
+	"github.com/Nicolas-Rigaudy/lazytf/internal/aws"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// mfaProfile returns the profile of the selected environment when it assumes a role
+// with MFA and no unexpired credentials were obtained for it yet, nil otherwise
+func (m Model) mfaProfile() *aws.Profile {
+	name, _ := m.awsSettings()
+	profile := aws.FindProfile(m.awsProfiles, name)
+	if !aws.NeedsMFA(m.awsProfiles, profile) {
+		return nil
+	}
+	if credentials := m.roleCredentials[name]; credentials != nil && !credentials.Expired() {
+		return nil
+	}
+	return profile
+}
+
+// showMFAPrompt asks for the MFA code of an assume-role profile
+// With a source_profile chain, the code is for the first role assumed with an MFA device
+func (m *Model) showMFAPrompt(profile *aws.Profile, retry tea.Msg, errorText string) {
+	name := profile.Name
+	hop := aws.MFAHop(m.awsProfiles, profile)
+	if hop == nil {
+		hop = profile
+	}
+	message := "Assume " + hop.RoleARN + "\nwith the MFA device " + hop.MFASerial
+	if hop != profile {
+		message += ",\nthen " + profile.RoleARN
+	}
+	m.modal.Show(ModalState{
+		Type:  ModalForm,
+		Title: "🔑 MFA Code for " + name,
+		Message: message + ".\n\n" +
+			"The temporary credentials are kept in memory until they expire.",
+		Fields: []FormField{
+			{Label: "MFA code", Kind: FormText, Placeholder: "123456"},
+		},
+		ErrorText: errorText,
+		OnSubmit: func(fields []FormField) tea.Msg {
+			return AssumeRoleMsg{Profile: name, Code: fields[0].Value, Retry: retry}
+		},
+	})
+}
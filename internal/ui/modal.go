@@ -16,8 +16,37 @@ const (
 	ModalConfirm           // Generic confirmation modal (yes/no)
 	ModalSelect            // Generic selection modal (pick from list)
 	ModalError             // Error display modal
+	ModalForm              // Form modal (toggles, choices and text inputs)
+	ModalInfo              // Information modal with key actions, stays open until Esc or closed by the model
 )
 
+// ModalAction is a key binding of a ModalInfo
+type ModalAction struct {
+	Key   string // e.g. "u"
+	Label string // e.g. "[u] Copy URL"
+	Do    func() tea.Msg
+}
+
+// FormFieldKind identifies how a form field is edited
+type FormFieldKind int
+
+const (
+	FormToggle FormFieldKind = iota // Checkbox, toggled with space
+	FormChoice                      // One of Options, cycled with ←/→
+	FormText                        // Free text input
+)
+
+// FormField is a single editable field of a ModalForm
+type FormField struct {
+	Label       string
+	Kind        FormFieldKind
+	Checked     bool     // For FormToggle
+	Value       string   // For FormText and FormChoice
+	Options     []string // For FormChoice
+	Placeholder string   // For FormText, shown when Value is empty
+	Multiline   bool     // For FormText, ctrl+n starts a new line (Enter still submits the form)
+}
+
 // ModalState holds the current modal state (pure data)
 type ModalState struct {
 	Type ModalType
@@ -33,8 +62,15 @@ type ModalState struct {
 	Selected int                             // Currently selected item index
 	OnSelect func(selectedIndex int) tea.Msg // Called when user presses Enter
 
-	// For ModalError
+	// For ModalError (also shown as a validation message in ModalForm)
 	ErrorText string
+
+	// For ModalForm (Selected is the focused field)
+	Fields   []FormField
+	OnSubmit func(fields []FormField) tea.Msg // Called when user presses Enter
+
+	// For ModalInfo (Message is the content)
+	Actions []ModalAction
 }
 
 // ═══════════════════════════════════════════════════════════════════════════
@@ -61,6 +97,11 @@ func (m Modal) IsActive() bool {
 	return m.state.Type != ModalNone
 }
 
+// Kind returns the type of the displayed modal, ModalNone if there is none
+func (m Modal) Kind() ModalType {
+	return m.state.Type
+}
+
 // Show displays a modal with the given state
 func (m *Modal) Show(state ModalState) {
 	m.state = state
@@ -104,7 +145,7 @@ func (m Modal) Update(msg tea.Msg) (Modal, tea.Cmd) {
 					m.state.Selected++
 				}
 			case "enter":
-				if m.state.OnSelect != nil {
+				if m.state.OnSelect != nil && len(m.state.Items) > 0 {
 					resultMsg := m.state.OnSelect(m.state.Selected)
 					m.state = ModalState{Type: ModalNone} // Close modal
 					// Wrap the message in a Cmd
@@ -115,6 +156,21 @@ func (m Modal) Update(msg tea.Msg) (Modal, tea.Cmd) {
 				return m, nil
 			}
 		}
+		if m.state.Type == ModalForm {
+			return m.updateForm(msg)
+		}
+		if m.state.Type == ModalInfo {
+			if msg.String() == "esc" {
+				m.state = ModalState{Type: ModalNone} // Close modal
+				return m, nil
+			}
+			for _, action := range m.state.Actions {
+				if msg.String() == action.Key && action.Do != nil {
+					resultMsg := action.Do()
+					return m, func() tea.Msg { return resultMsg }
+				}
+			}
+		}
 		if m.state.Type == ModalError {
 			switch msg.String() {
 			case "enter", "esc":
@@ -126,6 +182,67 @@ func (m Modal) Update(msg tea.Msg) (Modal, tea.Cmd) {
 	return m, nil
 }
 
+// updateForm handles input for a form modal
+// Navigation uses arrows/tab only, so letters can be typed into text fields
+func (m Modal) updateForm(msg tea.KeyMsg) (Modal, tea.Cmd) {
+	field := &m.state.Fields[m.state.Selected]
+
+	switch msg.String() {
+	case "up", "shift+tab":
+		if m.state.Selected > 0 {
+			m.state.Selected--
+		}
+	case "down", "tab":
+		if m.state.Selected < len(m.state.Fields)-1 {
+			m.state.Selected++
+		}
+	case "enter":
+		if m.state.OnSubmit != nil {
+			resultMsg := m.state.OnSubmit(m.state.Fields)
+			m.state = ModalState{Type: ModalNone} // Close modal
+			return m, func() tea.Msg { return resultMsg }
+		}
+	case "esc":
+		m.state = ModalState{Type: ModalNone} // Close modal
+		return m, nil
+	case "left", "right":
+		if field.Kind == FormChoice && len(field.Options) > 0 {
+			step := 1
+			if msg.String() == "left" {
+				step = len(field.Options) - 1
+			}
+			current := 0
+			for i, option := range field.Options {
+				if option == field.Value {
+					current = i
+				}
+			}
+			field.Value = field.Options[(current+step)%len(field.Options)]
+		}
+	case " ":
+		switch field.Kind {
+		case FormToggle:
+			field.Checked = !field.Checked
+		case FormText:
+			field.Value += " "
+		}
+	case "ctrl+n":
+		if field.Kind == FormText && field.Multiline {
+			field.Value += "\n"
+		}
+	case "backspace":
+		if field.Kind == FormText && len(field.Value) > 0 {
+			runes := []rune(field.Value)
+			field.Value = string(runes[:len(runes)-1])
+		}
+	default:
+		if field.Kind == FormText && msg.Type == tea.KeyRunes {
+			field.Value += string(msg.Runes)
+		}
+	}
+	return m, nil
+}
+
 // View renders the modal based on its current state
 func (m Modal) View(termWidth, termHeight int) string {
 	switch m.state.Type {
@@ -135,6 +252,10 @@ func (m Modal) View(termWidth, termHeight int) string {
 		return RenderSelectModal(m.state, termWidth, termHeight)
 	case ModalError:
 		return RenderErrorModal(m.state, termWidth, termHeight)
+	case ModalForm:
+		return RenderFormModal(m.state, termWidth, termHeight)
+	case ModalInfo:
+		return RenderInfoModal(m.state, termWidth, termHeight)
 	default:
 		return ""
 	}
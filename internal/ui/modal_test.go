@@ -0,0 +1,37 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestModalSelectEnter(t *testing.T) {
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+
+	t.Run("empty list", func(t *testing.T) {
+		modal := NewModal()
+		modal.Show(ModalState{
+			Type:     ModalSelect,
+			OnSelect: func(index int) tea.Msg { t.Errorf("OnSelect(%d) called without items", index); return nil },
+		})
+		modal, cmd := modal.Update(enter)
+		if cmd != nil || !modal.IsActive() {
+			t.Error("enter on an empty list closed the modal or returned a command")
+		}
+	})
+
+	t.Run("selected item", func(t *testing.T) {
+		modal := NewModal()
+		modal.Show(ModalState{
+			Type:     ModalSelect,
+			Items:    []string{"dev", "prod"},
+			OnSelect: func(index int) tea.Msg { return index },
+		})
+		modal, _ = modal.Update(tea.KeyMsg{Type: tea.KeyDown})
+		modal, cmd := modal.Update(enter)
+		if modal.IsActive() || cmd == nil || cmd() != 1 {
+			t.Error("enter didn't close the modal with the selected index")
+		}
+	})
+}
@@ -1,7 +1,10 @@
 package ui
 
 import (
+	"strings"
+
 	"github.com/Nicolas-Rigaudy/lazytf/internal/ui/theme"
+	"github.com/charmbracelet/lipgloss"
 )
 
 // ═══════════════════════════════════════════════════════════════════════════
@@ -30,9 +33,34 @@ func RenderConfirmModal(state ModalState, termWidth, termHeight int) string {
 	return builder.Render(termWidth, termHeight)
 }
 
+// maxVisibleSelectItems caps how many items a select modal shows at once,
+// longer lists scroll with the selection
+const maxVisibleSelectItems = 15
+
+// visibleRange returns the window of a long list to display, keeping selected in view
+func visibleRange(selected, total int) (int, int) {
+	if total <= maxVisibleSelectItems {
+		return 0, total
+	}
+	start := selected - maxVisibleSelectItems/2
+	if start < 0 {
+		start = 0
+	}
+	if start > total-maxVisibleSelectItems {
+		start = total - maxVisibleSelectItems
+	}
+	return start, start + maxVisibleSelectItems
+}
+
 func RenderSelectModal(state ModalState, termWidth, termHeight int) string {
+	start, end := visibleRange(state.Selected, len(state.Items))
+
 	content := ""
-	for i, item := range state.Items {
+	if start > 0 {
+		content += "  ↑ more\n"
+	}
+	for i := start; i < end; i++ {
+		item := state.Items[i]
 		if i == state.Selected {
 			content += "› " + item + "\n"
 
@@ -40,6 +68,9 @@ func RenderSelectModal(state ModalState, termWidth, termHeight int) string {
 			content += "  " + item + "\n"
 		}
 	}
+	if end < len(state.Items) {
+		content += "  ↓ more\n"
+	}
 
 	builder := ModalBuilder{
 		Title:   state.Title,
@@ -49,7 +80,7 @@ func RenderSelectModal(state ModalState, termWidth, termHeight int) string {
 			{Label: "[ESC] Cancel", Color: theme.Current.Red, Key: "esc"},
 		},
 		Width:       50,
-		Height:      len(state.Items) + 8, // Dynamic height based on items
+		Height:      strings.Count(content, "\n") + 8, // Dynamic height based on visible items
 		BorderColor: theme.Current.Blue,
 	}
 
@@ -76,3 +107,93 @@ func RenderErrorModal(state ModalState, termWidth, termHeight int) string {
 
 	return builder.Render(termWidth, termHeight)
 }
+
+// RenderInfoModal renders a message with one button per action and an Esc button to hide it
+func RenderInfoModal(state ModalState, termWidth, termHeight int) string {
+	var buttons []ModalButton
+	for _, action := range state.Actions {
+		buttons = append(buttons, ModalButton{Label: action.Label, Color: theme.Current.Green, Key: action.Key})
+	}
+	buttons = append(buttons, ModalButton{Label: "[Esc] Hide", Color: theme.Current.Overlay1, Key: "esc"})
+
+	builder := ModalBuilder{
+		Title:       state.Title,
+		Content:     state.Message,
+		Buttons:     buttons,
+		Width:       80,
+		Height:      14,
+		BorderColor: theme.Current.Blue,
+	}
+
+	return builder.Render(termWidth, termHeight)
+}
+
+// RenderFormModal renders a form with one field per line, the focused field marked with ›
+func RenderFormModal(state ModalState, termWidth, termHeight int) string {
+	var lines []string
+	if state.Message != "" {
+		lines = append(lines, state.Message, "")
+	}
+
+	start, end := visibleRange(state.Selected, len(state.Fields))
+	if start > 0 {
+		lines = append(lines, "  ↑ more")
+	}
+	for i := start; i < end; i++ {
+		field := state.Fields[i]
+		cursor := "  "
+		if i == state.Selected {
+			cursor = "› "
+		}
+
+		var line string
+		switch field.Kind {
+		case FormToggle:
+			box := "[ ]"
+			if field.Checked {
+				box = "[x]"
+			}
+			line = box + " " + field.Label
+		case FormChoice:
+			value := field.Value
+			if value == "" {
+				value = "default"
+			}
+			line = field.Label + ": ‹ " + value + " ›"
+		case FormText:
+			value := field.Value
+			if i == state.Selected {
+				value += "█"
+			} else if value == "" {
+				value = field.Placeholder
+			}
+			line = field.Label + ": " + value
+			if field.Multiline {
+				// Continuation lines are aligned below the first one
+				line = strings.ReplaceAll(line, "\n", "\n  "+strings.Repeat(" ", len([]rune(field.Label))+2))
+			}
+		}
+		lines = append(lines, cursor+line)
+	}
+	if end < len(state.Fields) {
+		lines = append(lines, "  ↓ more")
+	}
+
+	if state.ErrorText != "" {
+		lines = append(lines, "", lipgloss.NewStyle().Foreground(theme.Current.Red).Render("⚠️  "+state.ErrorText))
+	}
+
+	builder := ModalBuilder{
+		Title:   state.Title,
+		Content: lipgloss.NewStyle().Align(lipgloss.Left).Render(strings.Join(lines, "\n")),
+		Buttons: []ModalButton{
+			{Label: "[Enter] Continue", Color: theme.Current.Green, Key: "enter"},
+			{Label: "[ESC] Cancel", Color: theme.Current.Red, Key: "esc"},
+		},
+		Width:       70,
+		Height:      strings.Count(strings.Join(lines, "\n"), "\n") + 9,
+		BorderColor: theme.Current.Blue,
+	}
+
+	return builder.Render(termWidth, termHeight)
+}
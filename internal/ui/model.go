@@ -1,11 +1,15 @@
 package ui
 
 import (
+	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/Nicolas-Rigaudy/lazytf/internal/auth"
 	"github.com/Nicolas-Rigaudy/lazytf/internal/aws"
+	"github.com/Nicolas-Rigaudy/lazytf/internal/config"
 	"github.com/Nicolas-Rigaudy/lazytf/internal/executor"
 	"github.com/Nicolas-Rigaudy/lazytf/internal/terraform"
 	tea "github.com/charmbracelet/bubbletea"
@@ -38,6 +42,33 @@ type Model struct {
 	backendVarFiles     []terraform.BackendVarFile
 	selectedBackendFile *terraform.BackendVarFile
 	backendState        terraform.BackendState
+	binary              terraform.BinaryResolution           // engine binary resolved for the selected project
+	binaryPending       bool                                 // the binary is still being resolved in the background
+	runAll              bool                                 // run Terragrunt commands on every unit below the project
+	graph               *terraform.Graph                     // last loaded dependency graph of the selected project
+	commandRunning      bool                                 // a command is streaming output to the main panel
+	planForApply        *savedPlan                           // plan running before an apply, shown for confirmation once it is done
+	variables           []terraform.Variable                 // variable blocks declared by the selected project
+	coverage            map[string]terraform.CoverageReport  // env name -> variable coverage problems
+	secrets             map[string][]terraform.SecretFinding // env name -> literal secrets in its var and backend files
+	masker              terraform.SecretMasker               // hides detected secrets in displayed text
+	awsProfile          string                               // AWS profile picked with 'a', used for environments without a mapping
+	awsProfiles         []*aws.Profile                       // profiles found in ~/.aws/config and ~/.aws/credentials
+	ssoSessions         []*aws.SSOSession                    // [sso-session] entries of ~/.aws/config
+	awsConfigErr        error                                // why the AWS config files couldn't be read
+	providerAccounts    terraform.AccountConstraints         // account lists of the selected project's aws provider
+	identity            *aws.CallerIdentity                  // AWS identity commands run as, nil if unknown
+	identityErr         error                                // why the identity couldn't be resolved
+	identityKey         string                               // AWS environment the identity was resolved for
+	deviceAuth          *aws.DeviceAuthorization             // URL and code of a running no-browser login
+	cloudProviders      []auth.Provider                      // providers the selected project needs to log in to
+	authPicked          map[string]map[string]string         // environment (see regionKey) -> provider ID -> context picked (e.g. Azure subscription)
+	loginProvider       string                               // ID of the provider whose login command is running
+	skipLoginCheck      bool                                 // the next command runs despite the login warning
+	roleCredentials     map[string]*aws.RoleCredentials      // profile name -> temporary credentials obtained with an MFA code
+	awsRegions          map[string]string                    // project path + "|" + env name -> region picked with 'r'
+	config              config.Config
+	configErr           error // why the config file couldn't be loaded, it is never saved then
 	modal               Modal // Modal component
 }
 
@@ -49,6 +80,13 @@ func NewModel(projects []terraform.Project, mode terraform.Mode) Model {
 	var backendVarFiles []terraform.BackendVarFile
 
 	var backendState terraform.BackendState
+	var binary terraform.BinaryResolution
+
+	// Fall back to defaults if the config can't be read, the UI still works without it
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.DefaultConfig()
+	}
 
 	if mode == terraform.ModeSingleProject {
 		// Single-project mode setup
@@ -56,13 +94,16 @@ func NewModel(projects []terraform.Project, mode terraform.Mode) Model {
 		selectedProject = &projects[0]
 
 		// Discover var files and backends for the project
-		varFiles, _ = terraform.DiscoverVarFiles(selectedProject.Path)
+		varFiles = discoverEnvironments(cfg, selectedProject.Path)
 		sidebarItems := terraform.GetVarFileDisplayNames(varFiles)
-		backendVarFiles, _ = terraform.DiscoverBackendVarFiles(selectedProject.Path)
+		backendVarFiles, _ = terraform.DiscoverBackendVarFiles(selectedProject.Path, cfg.ConventionsFor(selectedProject.Path))
 
 		// Detect current backend initialization state
 		backendState = terraform.DetectCurrentBackend(selectedProject.Path, backendVarFiles)
 
+		// The binary matching the project's version constraints is resolved by Init
+		binary = pendingBinary(cfg, selectedProject.Path)
+
 		sidebar = NewSidebar(sidebarItems...)
 		sidebar.Title = selectedProject.Name
 		sidebar.InitializedEnv = backendState.DetectedEnv
@@ -108,8 +149,22 @@ func NewModel(projects []terraform.Project, mode terraform.Mode) Model {
 		backendVarFiles:     backendVarFiles,
 		selectedBackendFile: nil,
 		backendState:        backendState,
+		binary:              binary,
+		binaryPending:       selectedProject != nil,
+		configErr:           err,
+		config:              cfg,
 		modal:               modal,
 	}
+	m.loadAWSConfig()
+	if selectedProject != nil {
+		m.providerAccounts = terraform.ParseAWSAccountConstraints(selectedProject.Path)
+		m.cloudProviders = auth.ForTerraformProviders(terraform.UsedProviders(selectedProject.Path))
+	}
+
+	if selectedProject != nil {
+		m.refreshCoverage()
+		m.refreshSecrets()
+	}
 
 	// Set initial status bar text
 	m.statusBar.SetText(m.buildStatusText())
@@ -126,6 +181,494 @@ func (m *Model) updateFocusStates() {
 func (m *Model) prepareCommandExecution() {
 	m.mainPanel.Title = "⏳ Running Command"
 	m.mainPanel.Content = ""
+	if warning := m.awsProfileWarning(); warning != "" {
+		m.mainPanel.Content = "⚠️  " + warning + "\n\n"
+	}
+	m.commandRunning = true
+}
+
+// showVarFilePreview displays the variables set by the highlighted environment in the main panel
+func (m *Model) showVarFilePreview() {
+	if m.viewMode != ViewModeProjectDetail || m.commandRunning {
+		return
+	}
+	if m.sidebar.SelectedIndex >= len(m.varFiles) {
+		return
+	}
+
+	varFile := m.varFiles[m.sidebar.SelectedIndex]
+	m.mainPanel.Title = "📄 Variables: " + varFile.EnvName
+	values, err := terraform.ParseVarFile(varFile.FullPath)
+	if err != nil {
+		m.mainPanel.Content = varFile.Path + "\n\n❌ Failed to parse: " + err.Error()
+		return
+	}
+	m.mainPanel.Content = varFile.Path + "\n\n" + terraform.FormatVarValues(values) +
+		"\n--- Var Files (load order) ---\n" + terraform.FormatVarFileLayers(m.varFileLayers(varFile)) + "\n"
+
+	if report, ok := m.coverage[varFile.EnvName]; ok {
+		m.mainPanel.Content += "\n--- Variable Coverage ---\n" + terraform.FormatCoverageReport(report)
+	}
+
+	m.mainPanel.Content += "\n\n--- Secrets ---\n" + terraform.FormatSecretFindings(m.secrets[varFile.EnvName])
+	m.mainPanel.Content = m.masker.Mask(m.mainPanel.Content)
+}
+
+// refreshCoverage checks every environment of the selected project against the declared
+// variables and shows a warning next to the environments with problems
+func (m *Model) refreshCoverage() {
+	m.variables = terraform.ParseVariables(m.selectedProject.Path)
+	m.coverage = make(map[string]terraform.CoverageReport)
+	m.sidebar.Warnings = make(map[string]string)
+	env := append(os.Environ(), m.commandEnv()...)
+
+	for _, varFile := range m.varFiles {
+		values, err := terraform.LoadEnvironmentValues(m.varFileLayers(varFile))
+		if err != nil {
+			m.sidebar.Warnings[varFile.EnvName] = "parse error"
+			continue
+		}
+		report := terraform.CheckCoverage(m.variables, values, env)
+		m.coverage[varFile.EnvName] = report
+		if !report.IsClean() {
+			m.sidebar.Warnings[varFile.EnvName] = report.Summary()
+		}
+	}
+}
+
+// refreshSecrets scans every environment's var files and backend files for literal secrets,
+// flags the environments that have some and prepares the masker for displayed text
+func (m *Model) refreshSecrets() {
+	m.secrets = make(map[string][]terraform.SecretFinding)
+	var all []terraform.SecretFinding
+
+	for _, varFile := range m.varFiles {
+		var paths []string
+		for _, layer := range m.varFileLayers(varFile) {
+			paths = append(paths, layer.FullPath)
+		}
+		for _, backend := range terraform.MatchBackendsForEnv(varFile.EnvName, m.backendVarFiles) {
+			paths = append(paths, backend.FullPath)
+		}
+
+		findings := terraform.ScanFiles(m.selectedProject.Path, paths)
+		m.secrets[varFile.EnvName] = findings
+		all = append(all, findings...)
+
+		if len(findings) > 0 {
+			warning := strconv.Itoa(len(findings)) + " secret(s)"
+			if existing := m.sidebar.Warnings[varFile.EnvName]; existing != "" {
+				warning = existing + ", " + warning
+			}
+			m.sidebar.Warnings[varFile.EnvName] = warning
+		}
+	}
+
+	// Backend files not matched to any environment can hold secrets too
+	var backendPaths []string
+	for _, backend := range m.backendVarFiles {
+		backendPaths = append(backendPaths, backend.FullPath)
+	}
+	all = append(all, terraform.ScanFiles(m.selectedProject.Path, backendPaths)...)
+
+	m.masker = terraform.NewSecretMasker(all)
+}
+
+// discoverEnvironments finds the var files of a project that define an environment,
+// leaving out shared layers (e.g. common.tfvars) and auto-loaded files
+func discoverEnvironments(cfg config.Config, projectPath string) []terraform.VarFile {
+	varFiles, _ := terraform.DiscoverVarFiles(projectPath, cfg.VarFileRootsFor(projectPath), cfg.ConventionsFor(projectPath))
+	return terraform.EnvironmentVarFiles(projectPath, varFiles, cfg.VarFileLayersFor(projectPath))
+}
+
+// varFileLayers returns the ordered var files the given environment is built from
+func (m Model) varFileLayers(varFile terraform.VarFile) []terraform.VarFile {
+	return terraform.ResolveVarFileLayers(m.selectedProject.Path, varFile, m.config.VarFileLayersFor(m.selectedProject.Path))
+}
+
+// pendingBinary is the engine's PATH binary, used until resolveProjectBinary is done
+func pendingBinary(cfg config.Config, projectPath string) terraform.BinaryResolution {
+	engine, err := terraform.SelectEngine(cfg, projectPath)
+	return withEngineWarning(terraform.BinaryResolution{Engine: engine, Binary: engine.Binary(), Satisfied: true}, err)
+}
+
+// resolveProjectBinary selects the engine for a project and resolves the binary to run
+// in the background, as it runs the installed binaries to read their version
+func resolveProjectBinary(cfg config.Config, projectPath string) tea.Cmd {
+	return func() tea.Msg {
+		engine, err := terraform.SelectEngine(cfg, projectPath)
+		return BinaryResolvedMsg{
+			ProjectPath: projectPath,
+			Binary:      withEngineWarning(terraform.ResolveBinary(projectPath, engine, cfg.InstallDirs(string(engine))), err),
+		}
+	}
+}
+
+// withEngineWarning adds a config error about the engine name to the resolution's warning
+func withEngineWarning(resolution terraform.BinaryResolution, err error) terraform.BinaryResolution {
+	if err == nil {
+		return resolution
+	}
+	if resolution.Warning != "" {
+		resolution.Warning = err.Error() + "; " + resolution.Warning
+	} else {
+		resolution.Warning = err.Error()
+	}
+	return resolution
+}
+
+// runsAll reports whether Terragrunt commands run on every unit below the selected project
+func (m Model) runsAll() bool {
+	return m.selectedProject != nil && (m.runAll || m.selectedProject.IsStack)
+}
+
+// commandContext describes how commands for the selected project are started
+func (m Model) commandContext(projectPath string) terraform.CommandContext {
+	return terraform.CommandContext{
+		ProjectPath: projectPath,
+		Engine:      m.binary.Engine,
+		Binary:      m.binary.Binary,
+		Terragrunt:  m.selectedProject != nil && m.selectedProject.IsTerragrunt,
+		RunAll:      m.runsAll(),
+		Env:         m.commandEnv(),
+	}
+}
+
+// commandEnv returns the extra environment variables commands run with
+func (m Model) commandEnv() []string {
+	return auth.Env(m.authContext(false))
+}
+
+// authContext describes the selected environment to the auth providers
+func (m Model) authContext(noBrowser bool) auth.Context {
+	ctx := auth.Context{
+		Config:          m.config,
+		AWSProfile:      m.awsProfile,
+		RoleCredentials: m.roleCredentials,
+		NoBrowser:       noBrowser,
+	}
+	if m.selectedProject != nil {
+		ctx.ProjectPath = m.selectedProject.Path
+		if m.selectedVarFile != nil {
+			ctx.EnvName = m.selectedVarFile.EnvName
+			ctx.BackendRegion = m.backendRegion(ctx.EnvName)
+		}
+	}
+	ctx.AWSRegion = m.awsRegions[regionKey(ctx)]
+	ctx.Picked = m.authPicked[regionKey(ctx)]
+	ctx.AWSProfiles = m.awsProfiles
+	return ctx
+}
+
+// regionKey identifies the environment a region or an auth context is picked for
+func regionKey(ctx auth.Context) string {
+	return ctx.ProjectPath + "|" + ctx.EnvName
+}
+
+// backendRegion returns the region setting of the environment's backend file, "" if it has none
+func (m Model) backendRegion(envName string) string {
+	for _, backend := range terraform.MatchBackendsForEnv(envName, m.backendVarFiles) {
+		if region := backend.StringSettings()["region"]; region != "" {
+			return region
+		}
+	}
+	return ""
+}
+
+// awsSettings returns the AWS profile and region for the selected environment:
+// its mapping from the config, falling back to the profile picked with 'a'
+func (m Model) awsSettings() (string, string) {
+	return auth.AWSSettings(m.authContext(false))
+}
+
+// currentIdentityKey identifies the AWS environment commands would run in,
+// the identity has to be resolved again when it changes
+func (m Model) currentIdentityKey() string {
+	return strings.Join(m.commandEnv(), " ")
+}
+
+// resolveIdentity runs `aws sts get-caller-identity` in the background for the current
+// AWS environment; retry is sent again once the identity is known
+func (m Model) resolveIdentity(retry tea.Msg) tea.Cmd {
+	env := m.commandEnv()
+	key := m.currentIdentityKey()
+	return func() tea.Msg {
+		identity, err := aws.GetCallerIdentity(env)
+		return IdentityResolvedMsg{Key: key, Identity: identity, Err: err, Retry: retry}
+	}
+}
+
+// refreshIdentity resolves the AWS identity when the AWS environment changed and the identity
+// is needed: to check the account constraints, or to show the account of a project using AWS
+func (m Model) refreshIdentity() tea.Cmd {
+	if m.identityKnown() {
+		return nil
+	}
+	if m.accountConstraints().IsEmpty() && !m.usesAWS() {
+		return nil
+	}
+	return m.resolveIdentity(nil)
+}
+
+// identityKnown reports whether the identity (or why it couldn't be resolved) is known
+// for the current AWS environment
+func (m Model) identityKnown() bool {
+	return m.identityKey == m.currentIdentityKey() && (m.identity != nil || m.identityErr != nil)
+}
+
+// usesAWS reports whether the selected project uses the aws provider
+func (m Model) usesAWS() bool {
+	for _, provider := range m.cloudProviders {
+		if provider.ID() == "aws" {
+			return true
+		}
+	}
+	return false
+}
+
+// accountConstraints returns the accounts the selected environment may run in:
+// the account mapped to it in the config and the aws provider's account lists
+func (m Model) accountConstraints() terraform.AccountConstraints {
+	constraints := m.providerAccounts
+	if m.selectedProject != nil && m.selectedVarFile != nil {
+		if mapping, ok := m.config.AWSMappingFor(m.selectedProject.Path, m.selectedVarFile.EnvName); ok {
+			constraints.Expected = mapping.Account
+		}
+	}
+	return constraints
+}
+
+// accountProblem returns why the current identity may not run mutating commands, "" if it may
+func (m Model) accountProblem() string {
+	constraints := m.accountConstraints()
+	if constraints.IsEmpty() || m.identityKey != m.currentIdentityKey() {
+		return ""
+	}
+	if m.identity == nil {
+		if m.identityErr != nil {
+			return "could not verify the AWS account: " + m.identityErr.Error()
+		}
+		return "could not verify the AWS account"
+	}
+	return constraints.Check(m.identity.Account)
+}
+
+// guardAccount blocks state-writing commands when the AWS identity doesn't match the environment's
+// account. When the identity isn't known for the current AWS environment yet, it is resolved first
+// and retry is sent again; the returned command does that.
+func (m *Model) guardAccount(retry tea.Msg) (bool, tea.Cmd) {
+	if !m.accountConstraints().IsEmpty() && !m.identityKnown() {
+		m.statusBar.SetText("🔎 Checking AWS account...")
+		return false, m.resolveIdentity(retry)
+	}
+	problem := m.accountProblem()
+	if problem == "" {
+		return true, nil
+	}
+	m.modal.Show(ModalState{
+		Type:      ModalError,
+		Title:     "❌ Wrong AWS Account",
+		Message:   "Refusing to change the state: " + problem,
+		ErrorText: "Select the right AWS profile (a) or fix the account mapping in the config, then try again.",
+	})
+	return false, nil
+}
+
+// currentSSOSession returns the SSO session commands would authenticate with:
+// the one of the active profile, or the only configured session when no profile is set
+func (m Model) currentSSOSession() *aws.SSOSession {
+	profile, _ := m.awsSettings()
+	if profile != "" {
+		return aws.SessionForProfile(aws.FindProfile(m.awsProfiles, profile), m.ssoSessions)
+	}
+	if len(m.ssoSessions) == 1 {
+		return m.ssoSessions[0]
+	}
+	return nil
+}
+
+// awsProfileWarning returns a warning when the profile commands would run with doesn't exist
+func (m Model) awsProfileWarning() string {
+	profile, _ := m.awsSettings()
+	if profile == "" || aws.FindProfile(m.awsProfiles, profile) != nil {
+		return ""
+	}
+	if m.awsConfigErr != nil {
+		return "AWS profile \"" + profile + "\" not found, the AWS config could not be read: " + m.awsConfigErr.Error()
+	}
+	return "AWS profile \"" + profile + "\" does not exist in " + aws.SharedFilePaths()
+}
+
+// loadAWSConfig reads the profiles and SSO sessions of the shared AWS config files
+func (m *Model) loadAWSConfig() {
+	shared, err := aws.LoadSharedConfig()
+	m.awsConfigErr = err
+	if err != nil {
+		m.awsProfiles, m.ssoSessions = nil, nil
+		return
+	}
+	m.awsProfiles, m.ssoSessions = shared.Profiles, shared.Sessions
+}
+
+// guardCommand checks whether a command may start for the selected project.
+// When it may not, an error modal explaining why is shown and false is returned.
+// retry is sent again once an MFA prompt got the credentials the command needs.
+func (m *Model) guardCommand(retry tea.Msg) bool {
+	skipLogin := m.skipLoginCheck
+	m.skipLoginCheck = false
+
+	// The binary to run isn't known yet
+	if m.binaryPending {
+		m.statusBar.SetText("⏳ Resolving the " + m.binary.Engine.DisplayName() + " version, try again in a moment")
+		return false
+	}
+
+	// Refuse to run with a binary that doesn't satisfy the project's constraints in strict mode
+	if !m.binary.Satisfied && m.config.StrictVersionCheck {
+		m.modal.Show(ModalState{
+			Type:      ModalError,
+			Title:     "❌ " + m.binary.Engine.DisplayName() + " Version Mismatch",
+			ErrorText: m.binary.Warning + "\n\nInstall a matching version (e.g. with tfenv, tofuenv or asdf) and try again.",
+		})
+		return false
+	}
+
+	// Ask for the MFA code of assume-role profiles, the commands can't prompt for it
+	if profile := m.mfaProfile(); profile != nil {
+		m.showMFAPrompt(profile, retry, "")
+		return false
+	}
+
+	// Offer to log in first rather than letting the command fail on expired credentials
+	// The status is a guess (credential files, managed identities or metadata servers aren't
+	// seen), so the command can still run anyway
+	if skipLogin {
+		return true
+	}
+	ctx := m.authContext(false)
+	for _, provider := range m.loginProviders() {
+		status := provider.Status(ctx)
+		logins := provider.Logins(ctx)
+		if status.LoggedIn || len(logins) == 0 {
+			continue
+		}
+		providerID, login := provider.ID(), logins[0]
+		m.modal.Show(ModalState{
+			Type:  ModalSelect,
+			Title: "⚠️  " + provider.Name() + " Login May Be Needed",
+			Message: provider.Name() + ": " + status.Text + ".\n\n" +
+				"Log in first, or run anyway if the command gets its credentials another way.",
+			Items: []string{"Log in now (" + login.Label + "), then run the command again", "Run anyway"},
+			OnSelect: func(index int) tea.Msg {
+				if index == 1 {
+					return RunWithoutLoginMsg{Retry: retry}
+				}
+				return RunLoginMsg{Provider: providerID, Login: login}
+			},
+		})
+		return false
+	}
+	return true
+}
+
+// selectEnvForCommand resolves the environment a plan/apply runs with:
+// the selected env if any, otherwise the user picks one (or none if there are no var files)
+func (m *Model) selectEnvForCommand(command string) tea.Cmd {
+	if m.selectedVarFile != nil {
+		envName := m.selectedVarFile.EnvName
+		return func() tea.Msg {
+			return CommandEnvSelectedMsg{Command: command, EnvName: envName}
+		}
+	}
+	if len(m.varFiles) == 0 {
+		return func() tea.Msg {
+			return CommandEnvSelectedMsg{Command: command}
+		}
+	}
+
+	envNames := terraform.GetVarFileDisplayNames(m.varFiles)
+	m.modal.Show(ModalState{
+		Type:    ModalSelect,
+		Title:   "Terraform " + strings.ToUpper(command[:1]) + command[1:],
+		Message: "Choose an environment to " + command + " for " + m.selectedProject.Name,
+		Items:   envNames,
+		OnSelect: func(index int) tea.Msg {
+			return CommandEnvSelectedMsg{Command: command, EnvName: envNames[index]}
+		},
+	})
+	return nil
+}
+
+// commandWarnings returns warning lines for confirmation modals when the resolved
+// engine binary doesn't satisfy the project's version constraints or the AWS profile is missing
+func (m Model) commandWarnings() string {
+	warning := ""
+	if !m.binary.Satisfied {
+		warning += "\n\n⚠️  " + m.binary.Warning
+	}
+	if awsWarning := m.awsProfileWarning(); awsWarning != "" {
+		warning += "\n\n⚠️  " + awsWarning
+	}
+	return warning
+}
+
+// savedPlan is a plan saved to terraform.SavedPlanFile for an apply
+type savedPlan struct {
+	ProjectPath string
+	EnvName     string
+	Target      string // what the apply changes, e.g. "project network"
+	VarFiles    []terraform.VarFile
+}
+
+// showSavedPlan renders a saved plan in the background
+func (m Model) showSavedPlan(plan savedPlan) tea.Cmd {
+	ctx := m.commandContext(plan.ProjectPath)
+	return func() tea.Msg {
+		text, err := terraform.ShowPlan(ctx, terraform.SavedPlanFile)
+		return SavedPlanShownMsg{Plan: plan, Text: text, Err: err}
+	}
+}
+
+// projectDetails renders the details of the selected project from the current state
+func (m Model) projectDetails() string {
+	project := m.selectedProject
+	backendStateInfo := terraform.FormatBackendState(m.backendState)
+	content := "Project: " + project.Name + "\n" +
+		"Path: " + project.Path + "\n\n" +
+		"--- Backend Status ---\n" +
+		backendStateInfo + "\n" +
+		"--- Engine: " + m.binary.Engine.DisplayName() + " ---\n" +
+		terraform.FormatVersionDetails(m.binary) + "\n\n" +
+		"--- Available Environments ---\n" +
+		"Var Files: " + string(rune(len(m.varFiles)+'0')) + "\n" +
+		"Backend Configs: " + string(rune(len(m.backendVarFiles)+'0'))
+
+	// Show which state each backend config points to
+	if len(m.backendVarFiles) > 0 {
+		content += "\n\n--- Backend Configs ---\n"
+		for _, backend := range m.backendVarFiles {
+			envName := backend.EnvName
+			if envName == "" {
+				envName = "all environments"
+			}
+			content += "• " + backend.Name + " [" + envName + "]\n" +
+				"  " + strings.ReplaceAll(m.masker.Mask(terraform.FormatBackendSettings(backend)), "\n", "\n  ") + "\n"
+		}
+	}
+
+	// Show how Terragrunt units are wired together
+	if project.IsTerragrunt {
+		content += "\n\n--- Terragrunt ---\n"
+		tgConfig, err := terraform.ParseTerragruntConfig(project.Path)
+		if err != nil {
+			content += "Failed to parse " + terraform.TerragruntConfigFile + ": " + err.Error()
+		} else {
+			units := terraform.DiscoverTerragruntUnits(project.Path)
+			content += terraform.FormatTerragruntConfig(tgConfig, units)
+		}
+	}
+
+	return content
 }
 
 // buildStatusText creates dynamic status bar text based on current state
@@ -137,7 +680,17 @@ func (m Model) buildStatusText() string {
 		if m.mode == terraform.ModeMultiProject {
 			parts = append(parts, "Backspace: back", "│")
 		}
-		parts = append(parts, "i: init", "l: aws login", "│")
+		parts = append(parts, "i: init", "P: plan", "A: apply", "g: graph", "c: compare", "a: aws profile", "r: region", "l/L: login (no browser)")
+		if m.selectedProject != nil && m.selectedProject.IsStack {
+			parts = append(parts, "run-all (stack)")
+		} else if m.selectedProject != nil && m.selectedProject.IsTerragrunt {
+			runAll := "off"
+			if m.runAll {
+				runAll = "on"
+			}
+			parts = append(parts, "R: run-all ("+runAll+")")
+		}
+		parts = append(parts, "│")
 	}
 
 	parts = append(parts, "Tab: switch", "↑↓/jk: navigate", "Enter: select", "q: quit")
@@ -163,7 +716,64 @@ func (m Model) View() string {
 				return "No Env"
 			}
 		}(),
-		IsInitialized:   m.backendState.IsInitialized && m.selectedVarFile != nil && m.backendState.DetectedEnv == m.selectedVarFile.EnvName,
+		Region: func() string {
+			region, _ := m.awsSettings()
+			return region
+		}(),
+		IsInitialized: m.backendState.IsInitialized && m.selectedVarFile != nil && m.backendState.DetectedEnv == m.selectedVarFile.EnvName,
+		Version: func() string {
+			if m.selectedProject == nil {
+				return ""
+			}
+			if m.binaryPending {
+				return "resolving..."
+			}
+			return terraform.FormatBinaryResolution(m.binary)
+		}(),
+		Engine:         m.binary.Engine.DisplayName(),
+		VersionWarning: !m.binary.Satisfied || m.binary.Warning != "",
+		AWSProfile:     func() string { profile, _ := m.awsSettings(); return profile }(),
+		AWSWarning:     m.awsProfileWarning() != "",
+		AWSAccount: func() string {
+			if m.identity == nil || m.identityKey != m.currentIdentityKey() {
+				return ""
+			}
+			return m.identity.Account
+		}(),
+		AccountWarning: m.accountProblem() != "",
+		SSOStatus: func() string {
+			session := m.currentSSOSession()
+			if session == nil {
+				return ""
+			}
+			return aws.FormatTimeToExpiry(aws.RecentSSOToken(session))
+		}(),
+		MFAStatus: func() string {
+			name, _ := m.awsSettings()
+			if !aws.NeedsMFA(m.awsProfiles, aws.FindProfile(m.awsProfiles, name)) {
+				return ""
+			}
+			if credentials := m.roleCredentials[name]; credentials != nil && !credentials.Expired() {
+				return credentials.FormatExpiry()
+			}
+			return "code needed"
+		}(),
+		Clouds: func() []CloudStatus {
+			var clouds []CloudStatus
+			if m.selectedProject == nil {
+				return nil
+			}
+			ctx := m.authContext(false)
+			for _, provider := range m.cloudProviders {
+				// AWS has its own profile, account and SSO fields
+				if provider.ID() == "aws" {
+					continue
+				}
+				status := auth.RecentStatus(provider, ctx)
+				clouds = append(clouds, CloudStatus{Name: provider.Name(), Text: status.Text, LoggedIn: status.LoggedIn})
+			}
+			return clouds
+		}(),
 		LastCommand:     "",
 		LastCommandTime: time.Time{},
 	})
@@ -180,12 +790,30 @@ func (m Model) View() string {
 }
 
 func (m Model) Init() tea.Cmd {
+	if m.selectedProject != nil {
+		return tea.Batch(resolveProjectBinary(m.config, m.selectedProject.Path), m.refreshIdentity())
+	}
 	return nil
 }
 
+// reachesModelUnderModal reports whether a message is handled by the model even while a
+// modal is open: results of background work would otherwise be lost (e.g. the device
+// login modal stays open while the login command streams its output, and its copy
+// actions run while it is shown)
+func reachesModelUnderModal(msg tea.Msg) bool {
+	switch msg.(type) {
+	case tea.WindowSizeMsg,
+		executor.CommandOutputMsg, executor.CommandErrorMsg, executor.CommandCompletedMsg,
+		BinaryResolvedMsg, IdentityResolvedMsg, GraphLoadedMsg, SavedPlanShownMsg, SSORolesListedMsg, RoleAssumedMsg,
+		CopyToClipboardMsg:
+		return true
+	}
+	return false
+}
+
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// If modal is active, it gets priority for input handling
-	if m.modal.IsActive() {
+	if m.modal.IsActive() && !reachesModelUnderModal(msg) {
 		var cmd tea.Cmd
 		m.modal, cmd = m.modal.Update(msg)
 		return m, cmd
@@ -247,43 +875,122 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 
-		case "l":
-			// Trigger AWS SSO Login
-			sessions, err := aws.DiscoverSSOSessions()
-			if err != nil || len(sessions) == 0 {
-				m.modal.Show(ModalState{
-					Type:  ModalError,
-					Title: "❌ No AWS SSO Sessions Found",
-					ErrorText: "No AWS SSO sessions found in your AWS config file. Please configure at least one SSO session in ~/.aws/config " +
-						"before attempting to log in.",
-				})
-				return m, nil
+		case "P", "A":
+			if m.viewMode == ViewModeProjectDetail && m.selectedProject != nil {
+				command := "plan"
+				if msg.String() == "A" {
+					command = "apply"
+				}
+				return m, m.selectEnvForCommand(command)
+			}
+
+		case "g", "G":
+			// Load the dependency graph ("G" uses the plan graph)
+			if m.viewMode == ViewModeProjectDetail && m.selectedProject != nil {
+				options := terraform.GraphOptions{}
+				if msg.String() == "G" {
+					options.Type = "plan"
+				}
+				ctx := m.commandContext(m.selectedProject.Path)
+				ctx.RunAll = false // graph only makes sense for a single unit
+				m.mainPanel.Title = "⏳ Loading Graph"
+				m.mainPanel.Content = ""
+				return m, func() tea.Msg {
+					graph, err := terraform.LoadGraph(ctx, options)
+					return GraphLoadedMsg{Graph: graph, Err: err}
+				}
 			}
-			switch len(sessions) {
-			case 1:
-				// Only one session, run login directly
-				m.prepareCommandExecution()
-				return m, aws.RunSSOLogin(sessions[0])
-			default:
-				// Multiple sessions, show selection modal
-				var sessionNames []string
-				for _, session := range sessions {
-					sessionNames = append(sessionNames, session.Name)
+
+		case "c":
+			// Compare two environments' var files
+			if m.viewMode == ViewModeProjectDetail && m.selectedProject != nil {
+				if len(m.varFiles) < 2 {
+					m.modal.Show(ModalState{
+						Type:      ModalError,
+						Title:     "❌ Nothing to Compare",
+						ErrorText: "At least two var files are needed to compare environments.",
+					})
+					return m, nil
 				}
+				envNames := terraform.GetVarFileDisplayNames(m.varFiles)
 				m.modal.Show(ModalState{
-					Type:    ModalSelect,
-					Title:   "AWS SSO Login",
-					Message: "Select an AWS SSO session to log in:",
-					Items:   sessionNames,
+					Type:  ModalSelect,
+					Title: "Compare: First Environment",
+					Items: envNames,
 					OnSelect: func(index int) tea.Msg {
-						return RunAWSSSOLoginMsg{
-							Session: sessions[index],
-						}
+						return CompareLeftSelectedMsg{Left: index}
 					},
 				})
 				return m, nil
 			}
 
+		case "R":
+			// Toggle run-all for Terragrunt units (stacks always run-all)
+			if m.viewMode == ViewModeProjectDetail && m.selectedProject != nil && m.selectedProject.IsTerragrunt && !m.selectedProject.IsStack {
+				m.runAll = !m.runAll
+				m.statusBar.SetText(m.buildStatusText())
+				return m, nil
+			}
+
+		case "a":
+			// Pick the AWS profile commands run with
+			m.loadAWSConfig()
+			profiles := m.awsProfiles
+			if m.awsConfigErr != nil {
+				m.modal.Show(ModalState{
+					Type:      ModalError,
+					Title:     "❌ Cannot Read AWS Config",
+					ErrorText: m.awsConfigErr.Error(),
+				})
+				return m, nil
+			}
+			if len(profiles) == 0 && len(m.ssoSessions) == 0 {
+				m.modal.Show(ModalState{
+					Type:      ModalError,
+					Title:     "❌ No AWS Profiles Found",
+					ErrorText: "No profiles found in " + aws.SharedFilePaths() + ".",
+				})
+				return m, nil
+			}
+			items := []string{"(none, use the environment's default)"}
+			selected := 0
+			for i, profile := range profiles {
+				items = append(items, profile.Name+" — "+profile.Description())
+				if profile.Name == m.awsProfile {
+					selected = i + 1
+				}
+			}
+			// Last item: generate profiles from the accounts and roles of an SSO session
+			if len(m.ssoSessions) > 0 {
+				items = append(items, "➕ Add profiles from an SSO session...")
+			}
+			m.modal.Show(ModalState{
+				Type:     ModalSelect,
+				Title:    "AWS Profile",
+				Message:  "Select the AWS profile Terraform commands run with:",
+				Items:    items,
+				Selected: selected,
+				OnSelect: func(index int) tea.Msg {
+					if index == 0 {
+						return AWSProfileSelectedMsg{}
+					}
+					if index > len(profiles) {
+						return ListSSORolesMsg{}
+					}
+					return AWSProfileSelectedMsg{Profile: profiles[index-1].Name}
+				},
+			})
+			return m, nil
+
+		case "r":
+			// Pick the AWS region of the selected environment
+			m.showRegionSwitcher()
+			return m, nil
+
+		case "l", "L":
+			// Log in to the clouds the project uses, 'L' without opening a browser (e.g. on a remote machine)
+			return m, m.selectLoginProvider(msg.String() == "L")
+
 		case "tab":
 			m.focusIndex = (m.focusIndex + 1) % m.focusableCount
 			m.updateFocusStates()
@@ -295,11 +1002,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.selectedProject = nil
 				m.varFiles = nil
 				m.selectedVarFile = nil
+				m.runAll = false
+				m.graph = nil
 				sidebarItems := make([]string, len(m.projects))
 				for i, project := range m.projects {
 					sidebarItems[i] = project.Name
 				}
 				m.sidebar.Items = sidebarItems
+				m.sidebar.Warnings = nil
 				m.sidebar.Title = "Projects"
 				m.sidebar.SelectedIndex = 0
 				m.statusBar.SetText(m.buildStatusText())
@@ -336,7 +1046,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Delegate to focused component if not global key
 		switch m.focusIndex {
 		case 0:
+			previousIndex := m.sidebar.SelectedIndex
 			m.sidebar, cmd = m.sidebar.Update(msg)
+			if m.sidebar.SelectedIndex != previousIndex {
+				m.showVarFilePreview()
+			}
 		}
 		return m, cmd
 
@@ -346,37 +1060,38 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.selectedProject = selectedProject
 
 		// Discover var files and backends for the selected project
-		m.varFiles, _ = terraform.DiscoverVarFiles(selectedProject.Path)
+		m.varFiles = discoverEnvironments(m.config, selectedProject.Path)
 		sidebarItems := terraform.GetVarFileDisplayNames(m.varFiles)
-		m.backendVarFiles, _ = terraform.DiscoverBackendVarFiles(selectedProject.Path)
+		m.backendVarFiles, _ = terraform.DiscoverBackendVarFiles(selectedProject.Path, m.config.ConventionsFor(selectedProject.Path))
 
 		// Detect current backend initialization state
 		m.backendState = terraform.DetectCurrentBackend(selectedProject.Path, m.backendVarFiles)
 
+		// Resolve which engine binary matches the project's version constraints in the background
+		m.binary = pendingBinary(m.config, selectedProject.Path)
+		m.binaryPending = true
+		m.providerAccounts = terraform.ParseAWSAccountConstraints(selectedProject.Path)
+		m.cloudProviders = auth.ForTerraformProviders(terraform.UsedProviders(selectedProject.Path))
+
 		// Update sidebar
 		m.sidebar.Items = sidebarItems
 		m.sidebar.Title = selectedProject.Name
 		m.sidebar.SelectedIndex = 0 // Reset to first item
 		m.sidebar.InitializedEnv = m.backendState.DetectedEnv
+		m.refreshCoverage()
+		m.refreshSecrets()
 
 		// Switch to detail view
 		m.viewMode = ViewModeProjectDetail
 
 		// Update main panel with title and content
 		m.mainPanel.Title = "📋 Project Details"
-		backendStateInfo := terraform.FormatBackendState(m.backendState)
-		m.mainPanel.Content = "Project: " + selectedProject.Name + "\n" +
-			"Path: " + selectedProject.Path + "\n\n" +
-			"--- Backend Status ---\n" +
-			backendStateInfo + "\n" +
-			"--- Available Environments ---\n" +
-			"Var Files: " + string(rune(len(m.varFiles)+'0')) + "\n" +
-			"Backend Configs: " + string(rune(len(m.backendVarFiles)+'0'))
+		m.mainPanel.Content = m.projectDetails()
 
 		// Update status bar
 		m.statusBar.SetText(m.buildStatusText())
 
-		return m, nil
+		return m, resolveProjectBinary(m.config, selectedProject.Path)
 
 	case VarFileSelectedMsg:
 		// Get the selected var file
@@ -408,7 +1123,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				},
 			})
 		}
-		return m, nil
+		return m, m.refreshIdentity()
 
 	case InitEnvironmentSelectedMsg:
 		m.selectedVarFile, m.sidebar.SelectedIndex = terraform.FindVarFileByEnvName(msg.EnvName, m.varFiles)
@@ -423,23 +1138,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			})
 			return m, nil
 		case 1:
-			m.modal.Show(ModalState{
-				Type:    ModalConfirm,
-				Title:   "Confirm Terraform Init",
-				Message: "Initialize project " + m.selectedProject.Name + " with environment " + msg.EnvName + "?\n\nUsing backend: " + msg.Backends[0].Name,
-				OnConfirm: func() tea.Msg {
-					return RunInitMsg{
-						ProjectPath: m.selectedProject.Path,
-						Options: terraform.InitOptions{
-							BackendConfigFile: msg.Backends[0],
-							Reconfigure:       true,
-							Upgrade:           true,
-							Input:             false,
-						},
-					}
-				},
-			})
-			return m, nil
+			backend := msg.Backends[0]
+			return m, func() tea.Msg {
+				return InitBackendSelectedMsg{EnvName: msg.EnvName, Backend: backend}
+			}
 		default:
 			var backendNames []string
 			for _, b := range msg.Backends {
@@ -462,48 +1164,437 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case InitBackendSelectedMsg:
+		// Let the user review the init options before confirming
+		m.showInitForm(msg.EnvName, msg.Backend, m.projectInitDefaults(), "")
+		return m, nil
+
+	case InitOptionsEditedMsg:
+		options := initOptionsFromDefaults(msg.Defaults, msg.EnvName, msg.Backend)
+		if err := options.Validate(); err != nil {
+			m.showInitForm(msg.EnvName, msg.Backend, msg.Defaults, err.Error())
+			return m, nil
+		}
+
+		// The backend file has to complete the partial configuration of the backend block
+		backendWarning := ""
+		if missing := terraform.MissingBackendKeys(m.backendState.Declared, msg.Backend, options.BackendConfig, append(os.Environ(), m.commandEnv()...)); len(missing) > 0 && !options.NoBackend {
+			backendWarning = "\n\n⚠️  Missing " + m.backendState.Declared.Type + " backend settings: " + strings.Join(missing, ", ")
+		}
+
+		forceCopyWarning := ""
+		if options.ForceCopy {
+			forceCopyWarning = "\n\n⚠️  -force-copy copies the existing state to the new backend without asking"
+		}
+
+		projectPath, envName := m.selectedProject.Path, msg.EnvName
+		defaults := msg.Defaults
 		m.modal.Show(ModalState{
-			Type:    ModalConfirm,
-			Title:   "Confirm Terraform Init",
-			Message: "Initialize project " + m.selectedProject.Name + " with environment " + msg.EnvName + "?\n\nUsing backend: " + msg.Backend.Name,
+			Type:  ModalConfirm,
+			Title: "Confirm Terraform Init",
+			Message: "Initialize project " + m.selectedProject.Name + " with environment " + msg.EnvName + "?\n\n" +
+				"Using backend: " + msg.Backend.Name + "\n" +
+				m.masker.Mask(terraform.FormatBackendSettings(msg.Backend)) + "\n\n" +
+				"Command: " + strings.Join(terraform.InitArgs(options), " ") + forceCopyWarning + backendWarning + m.commandWarnings(),
 			OnConfirm: func() tea.Msg {
 				return RunInitMsg{
-					ProjectPath: m.selectedProject.Path,
-					Options: terraform.InitOptions{
-						BackendConfigFile: msg.Backend,
-						Reconfigure:       true,
-						Upgrade:           true,
-						Input:             false,
-					},
+					ProjectPath: projectPath,
+					EnvName:     envName,
+					Options:     options,
+					Defaults:    &defaults,
 				}
 			},
 		})
 		return m, nil
 
 	case RunInitMsg:
+		if !m.guardCommand(msg) {
+			return m, nil
+		}
+		// Init may migrate or create state, check the AWS account first
+		if ok, cmd := m.guardAccount(msg); !ok {
+			return m, cmd
+		}
 		m.prepareCommandExecution()
-		cmd := terraform.RunInit(msg.ProjectPath, msg.Options)
+		// Remember the confirmed options for the next init of this project
+		if msg.Defaults != nil {
+			if err := m.rememberInitDefaults(*msg.Defaults, msg.EnvName); err != nil {
+				m.mainPanel.Content += "⚠️  Init defaults not saved: " + err.Error() + "\n\n"
+			}
+		}
+		cmd := terraform.RunInit(m.commandContext(msg.ProjectPath), msg.Options)
 		return m, cmd
 
-	case RunAWSSSOLoginMsg:
+	case CommandEnvSelectedMsg:
+		var varFiles []terraform.VarFile
+		if msg.EnvName != "" {
+			m.selectedVarFile, m.sidebar.SelectedIndex = terraform.FindVarFileByEnvName(msg.EnvName, m.varFiles)
+			if m.selectedVarFile != nil {
+				varFiles = m.varFileLayers(*m.selectedVarFile)
+			}
+		}
+
+		if msg.Command == "plan" {
+			projectPath := m.selectedProject.Path
+			return m, func() tea.Msg {
+				return RunPlanMsg{
+					ProjectPath: projectPath,
+					Options:     terraform.PlanOptions{VarFiles: varFiles, Input: false},
+				}
+			}
+		}
+
+		// Apply changes infrastructure: plan to a file first, then ask with that plan and apply exactly it
+		target := "project " + m.selectedProject.Name
+		if m.runsAll() {
+			target = "every Terragrunt unit below " + m.selectedProject.Name
+		}
+		plan := &savedPlan{ProjectPath: m.selectedProject.Path, EnvName: msg.EnvName, Target: target, VarFiles: varFiles}
+		return m, func() tea.Msg {
+			return RunPlanMsg{
+				ProjectPath: plan.ProjectPath,
+				Options:     terraform.PlanOptions{VarFiles: varFiles, Out: terraform.SavedPlanFile, Input: false},
+				Apply:       plan,
+			}
+		}
+
+	case SavedPlanShownMsg:
+		if m.selectedProject == nil || m.selectedProject.Path != msg.Plan.ProjectPath {
+			return m, nil // another project was selected meanwhile
+		}
+		if msg.Err != nil {
+			m.modal.Show(ModalState{
+				Type:      ModalError,
+				Title:     "❌ Failed to Read the Saved Plan",
+				ErrorText: msg.Err.Error(),
+			})
+			return m, nil
+		}
+		m.mainPanel.Title = "📋 Plan to Apply"
+		m.mainPanel.Content = m.masker.Mask(msg.Text)
+
+		summary := strings.Join(terraform.SummarizePlan(msg.Text), "\n")
+		varFileInfo := "Var files:\n" + terraform.FormatVarFileLayers(msg.Plan.VarFiles)
+		projectPath := msg.Plan.ProjectPath
+		m.modal.Show(ModalState{
+			Type:  ModalConfirm,
+			Title: "Confirm Apply",
+			Message: "Apply this plan to " + msg.Plan.Target + " with environment " + msg.Plan.EnvName + "?\n\n" +
+				m.masker.Mask(summary) + "\n\n" + varFileInfo + m.commandWarnings(),
+			OnConfirm: func() tea.Msg {
+				return RunApplyMsg{
+					ProjectPath: projectPath,
+					Options:     terraform.ApplyOptions{PlanFile: terraform.SavedPlanFile, Input: false},
+				}
+			},
+		})
+		return m, nil
+
+	case GraphLoadedMsg:
+		if msg.Err != nil {
+			m.mainPanel.Title = "❌ Graph Failed"
+			m.modal.Show(ModalState{
+				Type:      ModalError,
+				Title:     "❌ Failed to Load Graph",
+				ErrorText: msg.Err.Error(),
+			})
+			return m, nil
+		}
+		if len(msg.Graph.Nodes) == 0 {
+			m.mainPanel.Title = "🕸 Dependency Graph"
+			m.mainPanel.Content = "The graph contains no resources."
+			return m, nil
+		}
+
+		m.graph = msg.Graph
+		m.mainPanel.Title = "🕸 Dependency Graph"
+		m.mainPanel.Content = strconv.Itoa(len(m.graph.Nodes)) + " resources loaded. Pick one to inspect."
+		nodes := m.graph.Nodes
+		m.modal.Show(ModalState{
+			Type:  ModalSelect,
+			Title: "Select Resource",
+			Items: nodes,
+			OnSelect: func(index int) tea.Msg {
+				return GraphNodeSelectedMsg{Node: nodes[index]}
+			},
+		})
+		return m, nil
+
+	case GraphNodeSelectedMsg:
+		m.mainPanel.Title = "🕸 Dependencies: " + msg.Node
+		m.mainPanel.Content = terraform.FormatDependencyTree(m.graph, msg.Node)
+		return m, nil
+
+	case CompareLeftSelectedMsg:
+		// Filter by index, display names aren't guaranteed to be unique
+		envNames := terraform.GetVarFileDisplayNames(m.varFiles)
+		var others []string
+		var otherIndexes []int
+		for i, name := range envNames {
+			if i != msg.Left {
+				others = append(others, name)
+				otherIndexes = append(otherIndexes, i)
+			}
+		}
+		if len(others) == 0 {
+			m.modal.Show(ModalState{
+				Type:      ModalError,
+				Title:     "❌ Nothing to Compare",
+				ErrorText: "There is no other environment to compare " + envNames[msg.Left] + " with.",
+			})
+			return m, nil
+		}
+		m.modal.Show(ModalState{
+			Type:  ModalSelect,
+			Title: "Compare " + envNames[msg.Left] + " With",
+			Items: others,
+			OnSelect: func(index int) tea.Msg {
+				return CompareVarFilesMsg{Left: msg.Left, Right: otherIndexes[index]}
+			},
+		})
+		return m, nil
+
+	case CompareVarFilesMsg:
+		if msg.Left >= len(m.varFiles) || msg.Right >= len(m.varFiles) {
+			return m, nil
+		}
+		left, right := m.varFiles[msg.Left], m.varFiles[msg.Right]
+		envNames := terraform.GetVarFileDisplayNames(m.varFiles)
+		leftName, rightName := envNames[msg.Left], envNames[msg.Right]
+
+		// Compare what Terraform sees: every layer of each environment merged
+		m.mainPanel.Title = "🔀 Compare: " + leftName + " ↔ " + rightName
+		leftLayers, rightLayers := m.varFileLayers(left), m.varFileLayers(right)
+		leftValues, err := terraform.LoadEnvironmentValues(leftLayers)
+		if err != nil {
+			m.mainPanel.Content = "❌ Failed to parse the var files of " + leftName + ": " + err.Error()
+			return m, nil
+		}
+		rightValues, err := terraform.LoadEnvironmentValues(rightLayers)
+		if err != nil {
+			m.mainPanel.Content = "❌ Failed to parse the var files of " + rightName + ": " + err.Error()
+			return m, nil
+		}
+
+		diffs := terraform.DiffVarValues(leftValues, rightValues)
+		m.mainPanel.Content = leftName + ":\n" + terraform.FormatVarFileLayers(leftLayers) + "\n" +
+			rightName + ":\n" + terraform.FormatVarFileLayers(rightLayers) + "\n\n" +
+			m.masker.Mask(terraform.FormatVarDiff(leftName, rightName, diffs))
+		return m, nil
+
+	case RunPlanMsg:
+		if !m.guardCommand(msg) {
+			return m, nil
+		}
+		m.prepareCommandExecution()
+		m.planForApply = msg.Apply
+		return m, terraform.RunPlan(m.commandContext(msg.ProjectPath), msg.Options)
+
+	case RunApplyMsg:
+		if !m.guardCommand(msg) {
+			return m, nil
+		}
+		// Check the AWS account before changing anything
+		if ok, cmd := m.guardAccount(msg); !ok {
+			return m, cmd
+		}
 		m.prepareCommandExecution()
-		return m, aws.RunSSOLogin(msg.Session)
+		return m, terraform.RunApply(m.commandContext(msg.ProjectPath), msg.Options)
+
+	case BinaryResolvedMsg:
+		if m.selectedProject == nil || m.selectedProject.Path != msg.ProjectPath {
+			return m, nil // another project was selected meanwhile
+		}
+		m.binary = msg.Binary
+		m.binaryPending = false
+		// Re-render the project details with the resolved engine if they are still shown
+		if m.mainPanel.Title == "📋 Project Details" {
+			m.mainPanel.Content = m.projectDetails()
+		}
+		return m, nil
+
+	case AWSProfileSelectedMsg:
+		m.awsProfile = msg.Profile
+		return m, m.refreshIdentity()
+
+	case IdentityResolvedMsg:
+		if msg.Key != m.currentIdentityKey() {
+			// The profile or environment changed meanwhile, this result is stale
+			if msg.Retry != nil {
+				return m, m.resolveIdentity(msg.Retry)
+			}
+			return m, nil
+		}
+		m.identity, m.identityErr, m.identityKey = msg.Identity, msg.Err, msg.Key
+		m.statusBar.SetText(m.buildStatusText())
+		if msg.Retry != nil {
+			retry := msg.Retry
+			return m, func() tea.Msg { return retry }
+		}
+		return m, nil
+
+	case LoginProviderSelectedMsg:
+		return m, m.showLogins(auth.Find(msg.Provider), msg.NoBrowser)
+
+	case RunWithoutLoginMsg:
+		m.skipLoginCheck = true
+		retry := msg.Retry
+		return m, func() tea.Msg { return retry }
+
+	case RunLoginMsg:
+		m.prepareCommandExecution()
+		m.deviceAuth = nil
+		if msg.Login.DeviceCode {
+			// Watch the output for the URL and code to show them in a modal
+			m.deviceAuth = &aws.DeviceAuthorization{}
+		}
+		m.loginProvider = msg.Provider
+		return m, executor.ExecuteStreaming(msg.Login.Command, msg.Login.Args, "")
+
+	case AssumeRoleMsg:
+		profile := aws.FindProfile(m.awsProfiles, msg.Profile)
+		if profile == nil {
+			return m, nil
+		}
+		if !aws.ValidMFACode(msg.Code) {
+			m.showMFAPrompt(profile, msg.Retry, "The MFA code must be 6 digits")
+			return m, nil
+		}
+		m.statusBar.SetText("🔑 Assuming " + profile.RoleARN + "...")
+		profiles := m.awsProfiles
+		return m, func() tea.Msg {
+			credentials, err := aws.AssumeRoleWithMFA(profiles, profile, msg.Code)
+			return RoleAssumedMsg{Profile: profile.Name, Credentials: credentials, Err: err, Retry: msg.Retry}
+		}
+
+	case RoleAssumedMsg:
+		if msg.Err != nil {
+			m.statusBar.SetText(m.buildStatusText())
+			m.modal.Show(ModalState{
+				Type:      ModalError,
+				Title:     "❌ Failed to Assume Role",
+				ErrorText: msg.Err.Error(),
+			})
+			return m, nil
+		}
+		if m.roleCredentials == nil {
+			m.roleCredentials = make(map[string]*aws.RoleCredentials)
+		}
+		m.roleCredentials[msg.Profile] = msg.Credentials
+		auth.ForgetStatuses()
+		m.statusBar.SetText("✅ MFA session for " + msg.Profile + " started (" + msg.Credentials.FormatExpiry() + ")")
+		return m, m.resolveIdentity(msg.Retry)
+
+	case AWSRegionSelectedMsg:
+		if m.awsRegions == nil {
+			m.awsRegions = make(map[string]string)
+		}
+		if msg.Region == "" {
+			delete(m.awsRegions, msg.Key)
+		} else {
+			m.awsRegions[msg.Key] = msg.Region
+		}
+		m.statusBar.SetText(m.buildStatusText())
+		return m, m.refreshIdentity()
+
+	case PickAuthContextMsg:
+		m.showAuthContextPicker(auth.Find(msg.Provider))
+		return m, nil
+
+	case AuthContextPickedMsg:
+		if m.authPicked == nil {
+			m.authPicked = make(map[string]map[string]string)
+		}
+		if m.authPicked[msg.Key] == nil {
+			m.authPicked[msg.Key] = make(map[string]string)
+		}
+		m.authPicked[msg.Key][msg.Provider] = msg.ID
+		m.statusBar.SetText("✅ " + auth.Find(msg.Provider).Name() + " " + msg.Label + " selected")
+		return m, nil
+
+	case ListSSORolesMsg:
+		if msg.Session == nil {
+			return m, m.selectSSOSessionForProfiles()
+		}
+		m.statusBar.SetText("🔎 Listing the accounts and roles of " + msg.Session.Name + "...")
+		session := msg.Session
+		return m, func() tea.Msg {
+			roles, err := aws.ListSSOAccountRoles(session)
+			return SSORolesListedMsg{Session: session, Roles: roles, Err: err}
+		}
+
+	case SSORolesListedMsg:
+		m.statusBar.SetText(m.buildStatusText())
+		m.showSSORolePicker(msg)
+		return m, nil
+
+	case AddSSOProfilesMsg:
+		added, err := aws.AddSSOProfiles(msg.Session, msg.Roles, msg.Region)
+		if err != nil {
+			m.modal.Show(ModalState{
+				Type:      ModalError,
+				Title:     "❌ Failed to Add AWS Profiles",
+				ErrorText: err.Error(),
+			})
+			return m, nil
+		}
+		m.loadAWSConfig()
+		if len(added) == 0 {
+			m.statusBar.SetText("ℹ️  The selected profiles already exist")
+		} else {
+			m.statusBar.SetText("✅ Added AWS profiles: " + strings.Join(added, ", "))
+		}
+		return m, nil
+
+	case CopyToClipboardMsg:
+		copyToClipboard(msg.Text)
+		m.statusBar.SetText("📋 Copied " + msg.What + " to clipboard")
+		return m, nil
 
 	case executor.CommandOutputMsg:
-		// Handle streaming output - append each line as it arrives
-		m.mainPanel.Content += msg.Line + "\n"
+		// Handle streaming output - append each line as it arrives, secrets masked
+		m.mainPanel.Content += m.masker.Mask(msg.Line) + "\n"
+		if m.deviceAuth != nil && m.deviceAuth.ParseLine(msg.Line) {
+			m.showDeviceAuthorization()
+		}
 
 		// Return the ListenNext command to keep receiving messages
 		return m, msg.ListenNext
 
+	case executor.CommandErrorMsg:
+		// Command could not be started
+		m.mainPanel.Title = "❌ Command Failed"
+		m.mainPanel.Content += m.masker.Mask(msg.Output+": "+msg.Error.Error()) + "\n"
+		m.commandRunning = false
+		m.planForApply = nil
+		m.closeDeviceAuthorization()
+		m.finishLogin(false)
+		return m, nil
+
 	case executor.CommandCompletedMsg:
 		// Command finished - update title and refresh state
 		m.mainPanel.Title = "✅ Command Completed"
+		if msg.ExitCode != 0 {
+			m.mainPanel.Title = "❌ Command Failed (exit code " + strconv.Itoa(msg.ExitCode) + ")"
+		}
+		m.commandRunning = false
+		plan := m.planForApply
+		m.planForApply = nil
+		m.closeDeviceAuthorization()
+		if cmd := m.finishLogin(true); cmd != nil {
+			return m, cmd
+		}
+		if m.selectedProject == nil {
+			return m, nil
+		}
 
 		// Refresh backend state to update sidebar indicators
 		m.backendState = terraform.DetectCurrentBackend(m.selectedProject.Path, m.backendVarFiles)
 		m.sidebar.InitializedEnv = m.backendState.DetectedEnv
 
+		// A plan saved for an apply is shown for confirmation, unless planning failed
+		if plan != nil && msg.ExitCode == 0 {
+			return m, m.showSavedPlan(*plan)
+		}
 		return m, nil
 
 	case tea.WindowSizeMsg:
@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"sort"
+
+	"github.com/Nicolas-Rigaudy/lazytf/internal/auth"
+	"github.com/Nicolas-Rigaudy/lazytf/internal/aws"
+	"github.com/Nicolas-Rigaudy/lazytf/internal/config"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// showRegionSwitcher lets the user pick the AWS region of the selected environment
+// The first item goes back to the region from the config, profile or backend file;
+// regions already used there are listed before the others
+func (m *Model) showRegionSwitcher() {
+	ctx := m.authContext(false)
+	key := regionKey(ctx)
+
+	automatic := ctx
+	automatic.AWSRegion = ""
+	region, source := auth.AWSRegion(automatic)
+	first := "(automatic: none)"
+	if region != "" {
+		first = "(automatic: " + region + " from " + source + ")"
+	}
+
+	regions := m.knownRegions(ctx)
+	seen := make(map[string]bool)
+	for _, region := range regions {
+		seen[region] = true
+	}
+	for _, region := range aws.Regions {
+		if !seen[region] {
+			regions = append(regions, region)
+		}
+	}
+
+	items := append([]string{first}, regions...)
+	selected := 0
+	for i, region := range regions {
+		if region == ctx.AWSRegion {
+			selected = i + 1
+		}
+	}
+
+	title := "AWS Region"
+	if ctx.EnvName != "" {
+		title += " for " + ctx.EnvName
+	}
+	m.modal.Show(ModalState{
+		Type:     ModalSelect,
+		Title:    title,
+		Message:  "Select the region commands run in (AWS_REGION):",
+		Items:    items,
+		Selected: selected,
+		OnSelect: func(index int) tea.Msg {
+			if index == 0 {
+				return AWSRegionSelectedMsg{Key: key}
+			}
+			return AWSRegionSelectedMsg{Key: key, Region: regions[index-1]}
+		},
+	})
+}
+
+// knownRegions returns the regions used by the config mappings, profiles and
+// backend files of the project, sorted
+func (m Model) knownRegions(ctx auth.Context) []string {
+	found := make(map[string]bool)
+	mappings := append(append([]config.AWSMapping{}, m.config.ProjectSettings(ctx.ProjectPath).AWS...), m.config.AWS...)
+	for _, mapping := range mappings {
+		found[mapping.Region] = true
+	}
+	for _, profile := range m.awsProfiles {
+		found[profile.Region] = true
+	}
+	for _, backend := range m.backendVarFiles {
+		found[backend.StringSettings()["region"]] = true
+	}
+	delete(found, "")
+
+	var regions []string
+	for region := range found {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+	return regions
+}
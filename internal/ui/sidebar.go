@@ -42,6 +42,7 @@ type SidebarModel struct {
 	Height         int
 	IsFocused      bool
 	InitializedEnv string
+	Warnings       map[string]string // item -> short warning shown next to it
 }
 
 func NewSidebar(items ...string) SidebarModel {
@@ -85,6 +86,9 @@ func (m SidebarModel) View() string {
 		if m.InitializedEnv != "" && item == m.InitializedEnv {
 			displayItem = item + " ✅ Initialized"
 		}
+		if warning := m.Warnings[item]; warning != "" {
+			displayItem += " ⚠️  " + warning
+		}
 
 		if i == m.SelectedIndex {
 			items = append(items, highlightedItemStyle.Render(displayItem))
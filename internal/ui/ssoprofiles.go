@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/Nicolas-Rigaudy/lazytf/internal/aws"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// selectSSOSessionForProfiles asks which SSO session to generate profiles from,
+// listing its roles directly when there is only one
+func (m *Model) selectSSOSessionForProfiles() tea.Cmd {
+	sessions := m.ssoSessions
+	if len(sessions) == 1 {
+		return func() tea.Msg { return ListSSORolesMsg{Session: sessions[0]} }
+	}
+	var names []string
+	for _, session := range sessions {
+		names = append(names, session.Name+" — "+session.StartURL)
+	}
+	m.modal.Show(ModalState{
+		Type:    ModalSelect,
+		Title:   "Add AWS Profiles",
+		Message: "Select the SSO session to list accounts and roles from:",
+		Items:   names,
+		OnSelect: func(index int) tea.Msg {
+			return ListSSORolesMsg{Session: sessions[index]}
+		},
+	})
+	return nil
+}
+
+// showSSORolePicker shows the roles of an SSO session as a form of checkboxes,
+// roles that already have a profile are left out
+func (m *Model) showSSORolePicker(msg SSORolesListedMsg) {
+	if msg.Err != nil {
+		m.modal.Show(ModalState{
+			Type:      ModalError,
+			Title:     "❌ Failed to List SSO Accounts",
+			ErrorText: msg.Err.Error() + "\n\nPress 'l' to log in to the SSO session first if needed.",
+		})
+		return
+	}
+
+	var roles []aws.SSOAccountRole
+	// The first field is the region of the new profiles, the SSO session's by default
+	fields := []FormField{{Label: "Region", Kind: FormText, Value: msg.Session.Region, Placeholder: "none"}}
+	for _, role := range msg.Roles {
+		if m.hasProfileFor(msg.Session, role) {
+			continue
+		}
+		roles = append(roles, role)
+		fields = append(fields, FormField{
+			Label: role.AccountName + " (" + role.AccountID + ") / " + role.RoleName + " → " + role.ProfileName(),
+			Kind:  FormToggle,
+		})
+	}
+	if len(roles) == 0 {
+		m.modal.Show(ModalState{
+			Type:    ModalInfo,
+			Title:   "ℹ️  Nothing to Add",
+			Message: "Every account and role of " + msg.Session.Name + " already has a profile.",
+		})
+		return
+	}
+
+	session := msg.Session
+	m.modal.Show(ModalState{
+		Type:    ModalForm,
+		Title:   "Add AWS Profiles",
+		Message: "Select the roles to add as profiles to your AWS config file:",
+		Fields:  fields,
+		OnSubmit: func(fields []FormField) tea.Msg {
+			var selected []aws.SSOAccountRole
+			for i, field := range fields[1:] {
+				if field.Checked {
+					selected = append(selected, roles[i])
+				}
+			}
+			if len(selected) == 0 {
+				return nil
+			}
+			return AddSSOProfilesMsg{Session: session, Roles: selected, Region: strings.TrimSpace(fields[0].Value)}
+		},
+	})
+}
+
+// hasProfileFor reports whether a profile already uses the role through the session
+func (m Model) hasProfileFor(session *aws.SSOSession, role aws.SSOAccountRole) bool {
+	for _, profile := range m.awsProfiles {
+		if profile.SSOAccountID == role.AccountID && profile.SSORoleName == role.RoleName &&
+			(profile.SSOSession == session.Name || (profile.SSOStartURL != "" && profile.SSOStartURL == session.StartURL)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/Nicolas-Rigaudy/lazytf/internal/aws"
+)
+
+func TestSelectSSOSessionForProfiles(t *testing.T) {
+	corp := &aws.SSOSession{Name: "corp", StartURL: "https://corp.awsapps.com/start"}
+
+	m := Model{modal: NewModal(), ssoSessions: []*aws.SSOSession{corp}}
+	cmd := m.selectSSOSessionForProfiles()
+	if m.modal.IsActive() || cmd == nil {
+		t.Fatal("a single session should list its roles without a picker")
+	}
+	if msg, ok := cmd().(ListSSORolesMsg); !ok || msg.Session != corp {
+		t.Errorf("command returned %#v, want ListSSORolesMsg for corp", cmd())
+	}
+
+	m = Model{modal: NewModal(), ssoSessions: []*aws.SSOSession{corp, {Name: "lab"}}}
+	if cmd := m.selectSSOSessionForProfiles(); cmd != nil || m.modal.Kind() != ModalSelect || len(m.modal.state.Items) != 2 {
+		t.Errorf("several sessions should show a picker, got modal %v with %d items", m.modal.Kind(), len(m.modal.state.Items))
+	}
+}
+
+func TestShowSSORolePicker(t *testing.T) {
+	corp := &aws.SSOSession{Name: "corp", Region: "eu-west-1"}
+	roles := []aws.SSOAccountRole{
+		{AccountID: "123456789012", AccountName: "prod", RoleName: "Admin"},
+		{AccountID: "210987654321", AccountName: "prod", RoleName: "Admin"},
+		{AccountID: "111111111111", AccountName: "dev", RoleName: "ReadOnly"},
+	}
+	existing := []*aws.Profile{{Name: "dev", SSOSession: "corp", SSOAccountID: "111111111111", SSORoleName: "ReadOnly"}}
+
+	m := Model{modal: NewModal(), awsProfiles: existing}
+	m.showSSORolePicker(SSORolesListedMsg{Session: corp, Roles: roles})
+	if m.modal.Kind() != ModalForm {
+		t.Fatalf("modal = %v, want a form", m.modal.Kind())
+	}
+	want := []string{
+		"Region",
+		"prod (123456789012) / Admin → prod-admin-123456789012",
+		"prod (210987654321) / Admin → prod-admin-210987654321",
+	}
+	fields := m.modal.state.Fields
+	if len(fields) != len(want) {
+		t.Fatalf("form has %d fields, want %d", len(fields), len(want))
+	}
+	for i, field := range fields {
+		if field.Label != want[i] {
+			t.Errorf("field %d = %q, want %q", i, field.Label, want[i])
+		}
+	}
+	if fields[0].Value != "eu-west-1" {
+		t.Errorf("region = %q, want the session's eu-west-1", fields[0].Value)
+	}
+
+	// Selecting the second role adds it alone, in the region of the form
+	fields[2].Checked = true
+	fields[0].Value = " us-east-1 "
+	msg, ok := m.modal.state.OnSubmit(fields).(AddSSOProfilesMsg)
+	if !ok || len(msg.Roles) != 1 || msg.Roles[0].AccountID != "210987654321" || msg.Region != "us-east-1" {
+		t.Errorf("OnSubmit() = %#v, want the 210987654321 role in us-east-1", msg)
+	}
+
+	// With every role already added there is nothing to pick
+	m = Model{modal: NewModal(), awsProfiles: existing}
+	m.showSSORolePicker(SSORolesListedMsg{Session: corp, Roles: roles[2:]})
+	if m.modal.Kind() != ModalInfo {
+		t.Errorf("modal = %v, want an info modal", m.modal.Kind())
+	}
+}